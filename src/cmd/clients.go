@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/forrestcai35/lane/internal/currency"
+	"github.com/forrestcai35/lane/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Address book flags
+	bookName     string
+	bookEmails   []string
+	bookCurrency string
+	bookTerms    string
+	bookAddress  string
+	bookTaxID    string
+)
+
+var clientsCmd = &cobra.Command{
+	Use:     "clients",
+	Aliases: []string{"client"},
+	Short:   "Manage your client address book",
+	Long: `Save the clients you bill regularly under a short alias.
+
+Passing the alias to --client fills in the client's name, billing emails,
+currency, payment terms, address and tax ID. Flags you pass explicitly
+still take precedence.`,
+	Example: `  lane clients add acme --name "Acme Corp" --email ap@acme.com --currency eur --terms 30
+  lane 500 --client acme --desc "Consulting"`,
+}
+
+var clientsAddCmd = &cobra.Command{
+	Use:   "add <alias>",
+	Short: "Add a client",
+	Example: `  lane clients add acme --name "Acme Corp" --email ap@acme.com --email cfo@acme.com \
+    --currency eur --terms 30 --address "1 Main St, Springfield" --tax-id DE123456789`,
+	Args: cobra.ExactArgs(1),
+	RunE: runClientsAdd,
+}
+
+var clientsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List clients",
+	Args:  cobra.NoArgs,
+	RunE:  runClientsList,
+}
+
+var clientsEditCmd = &cobra.Command{
+	Use:   "edit <alias>",
+	Short: "Change a client",
+	Long: `Change a client's details. Only the flags you pass are changed.
+--email replaces every billing email, and --terms "" clears the default terms.`,
+	Example: `  lane clients edit acme --currency gbp
+  lane clients edit acme --email billing@acme.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: runClientsEdit,
+}
+
+var clientsRemoveCmd = &cobra.Command{
+	Use:     "remove <alias>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a client",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runClientsRemove,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{clientsAddCmd, clientsEditCmd} {
+		cmd.Flags().StringVar(&bookName, "name", "", "Billing name")
+		cmd.Flags().StringArrayVar(&bookEmails, "email", nil, "Billing email (repeatable, the first is the recipient)")
+		cmd.Flags().StringVar(&bookCurrency, "currency", "", "Default currency code")
+		cmd.Flags().StringVar(&bookTerms, "terms", "", "Default payment terms in days")
+		cmd.Flags().StringVar(&bookAddress, "address", "", "Billing address")
+		cmd.Flags().StringVar(&bookTaxID, "tax-id", "", "VAT number or other tax ID")
+		cmd.RegisterFlagCompletionFunc("currency", completeCurrency)
+	}
+	clientsRemoveCmd.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "Skip the confirmation prompt")
+
+	clientsCmd.AddCommand(clientsAddCmd)
+	clientsCmd.AddCommand(clientsListCmd)
+	clientsCmd.AddCommand(clientsEditCmd)
+	clientsCmd.AddCommand(clientsRemoveCmd)
+	rootCmd.AddCommand(clientsCmd)
+}
+
+func runClientsAdd(cmd *cobra.Command, args []string) error {
+	entry := config.Client{Alias: strings.ToLower(args[0]), Name: bookName}
+	if entry.Name == "" {
+		entry.Name = args[0]
+	}
+
+	if err := applyClientFlags(cmd, &entry); err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return usageError(err)
+	}
+
+	if err := config.AddClient(entry); err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	fmt.Println(ui.FormatSuccess("Added " + entry.Name + " as " + entry.Alias))
+	return nil
+}
+
+func runClientsList(cmd *cobra.Command, args []string) error {
+	clients, err := config.LoadClients()
+	if err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	if len(clients) == 0 {
+		fmt.Println(ui.Subtle.Render("No clients yet. Add one with 'lane clients add <alias> --name <name>'."))
+		return nil
+	}
+
+	rows := make([][]string, 0, len(clients))
+	for _, c := range clients {
+		terms := ""
+		if c.Terms != nil {
+			terms = fmt.Sprintf("net %d", *c.Terms)
+		}
+		rows = append(rows, []string{
+			c.Alias,
+			c.Name,
+			strings.Join(c.Emails, ", "),
+			strings.ToUpper(c.Currency),
+			terms,
+			c.TaxID,
+		})
+	}
+
+	fmt.Println(ui.Table([]string{"Alias", "Name", "Emails", "Currency", "Terms", "Tax ID"}, rows))
+	return nil
+}
+
+func runClientsEdit(cmd *cobra.Command, args []string) error {
+	entry, err := findClientByAlias(args[0])
+	if err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	if cmd.Flags().Changed("name") {
+		entry.Name = bookName
+	}
+
+	if err := applyClientFlags(cmd, entry); err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return usageError(err)
+	}
+
+	if err := config.UpdateClient(*entry); err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	fmt.Println(ui.FormatSuccess("Updated " + entry.Alias))
+	return nil
+}
+
+func runClientsRemove(cmd *cobra.Command, args []string) error {
+	entry, err := findClientByAlias(args[0])
+	if err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	if !skipConfirm {
+		if err := confirm(fmt.Sprintf("Remove %s (%s) from your address book?", entry.Alias, entry.Name)); err != nil {
+			fmt.Println(ui.FormatError(err.Error()))
+			return err
+		}
+	}
+
+	if err := config.RemoveClient(entry.Alias); err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	fmt.Println(ui.FormatSuccess("Removed " + entry.Alias))
+	return nil
+}
+
+// findClientByAlias returns the address book entry with the exact alias
+func findClientByAlias(alias string) (*config.Client, error) {
+	clients, err := config.LoadClients()
+	if err != nil {
+		return nil, err
+	}
+	for i := range clients {
+		if clients[i].Alias == strings.ToLower(alias) {
+			return &clients[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no client with alias %q (see 'lane clients list')", alias)
+}
+
+// applyClientFlags copies the address book flags that were passed onto an entry
+func applyClientFlags(cmd *cobra.Command, entry *config.Client) error {
+	flags := cmd.Flags()
+
+	if flags.Changed("email") {
+		entry.Emails = nil
+		for _, email := range bookEmails {
+			email = strings.TrimSpace(email)
+			if !strings.Contains(email, "@") {
+				return fmt.Errorf("invalid email: %s", email)
+			}
+			entry.Emails = append(entry.Emails, email)
+		}
+	}
+
+	if flags.Changed("currency") {
+		entry.Currency = ""
+		if bookCurrency != "" {
+			cur, ok := currency.Lookup(bookCurrency)
+			if !ok {
+				return fmt.Errorf("unknown currency: %s", bookCurrency)
+			}
+			entry.Currency = strings.ToLower(cur.Code)
+		}
+	}
+
+	if flags.Changed("terms") {
+		entry.Terms = nil
+		if bookTerms != "" {
+			days, err := strconv.Atoi(bookTerms)
+			if err != nil || days < 0 {
+				return fmt.Errorf("invalid payment terms %q (expected a number of days)", bookTerms)
+			}
+			entry.Terms = &days
+		}
+	}
+
+	if flags.Changed("address") {
+		entry.Address = bookAddress
+	}
+	if flags.Changed("tax-id") {
+		entry.TaxID = bookTaxID
+	}
+
+	return nil
+}
+
+// applyClient fills an invoice request's client details from an address
+// book entry. The email is only filled in when keepEmail is false; the
+// caller applies the entry's currency and terms.
+func applyClient(req *api.InvoiceRequest, entry *config.Client, keepEmail bool) {
+	req.ClientName = entry.Name
+	req.CustomerID = entry.CustomerID
+	if !keepEmail && len(entry.Emails) > 0 {
+		req.ClientEmail = entry.Emails[0]
+		req.CCEmails = entry.Emails[1:]
+	}
+	req.ClientAddress = entry.Address
+	req.ClientTaxID = entry.TaxID
+}
@@ -0,0 +1,394 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/forrestcai35/lane/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Sync flags
+	syncPrefer string
+	syncDryRun bool
+)
+
+var clientsSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Reconcile the address book with your Stripe customers",
+	Long: `Two-way sync between the local address book and Stripe customers.
+
+Clients and customers are matched by email (or by a previously linked
+customer ID). Clients missing from Stripe are created there, customers
+missing locally are added to the address book, and matched pairs are
+linked so new invoices reuse the existing Stripe customer. Clients with
+no email are skipped, since they can't be matched again later.
+
+When a matched pair disagrees, the differences are shown side by side and
+you pick which side to keep. --prefer resolves every conflict the same
+way without prompting.`,
+	Example: `  lane clients sync
+  lane clients sync --dry-run
+  lane clients sync --prefer remote`,
+	Args: cobra.NoArgs,
+	RunE: runClientsSync,
+}
+
+func init() {
+	clientsSyncCmd.Flags().StringVar(&syncPrefer, "prefer", "", "Resolve conflicts without prompting (local, remote)")
+	clientsSyncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "Show what would change without changing anything")
+	clientsCmd.AddCommand(clientsSyncCmd)
+}
+
+// fieldDiff is one field that differs between a client and a customer
+type fieldDiff struct {
+	field  string
+	local  string
+	remote string
+}
+
+// syncPair is a local client matched to a remote customer
+type syncPair struct {
+	local  config.Client
+	remote api.Customer
+	diffs  []fieldDiff
+}
+
+// syncPlan is everything sync would do
+type syncPlan struct {
+	push      []config.Client // Local only: create in Stripe
+	skip      []config.Client // Local only without an email: left alone
+	pull      []api.Customer  // Remote only: add to the address book
+	link      []syncPair      // Matched without differences
+	conflicts []syncPair      // Matched with differences
+}
+
+func runClientsSync(cmd *cobra.Command, args []string) error {
+	if syncPrefer != "" && syncPrefer != "local" && syncPrefer != "remote" {
+		err := fmt.Errorf("invalid --prefer %q (expected local or remote)", syncPrefer)
+		fmt.Println(formatError(err))
+		return usageError(err)
+	}
+
+	locals, err := config.LoadClients()
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	client, err := api.NewClient()
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	fmt.Println(ui.FormatStep("Fetching Stripe customers..."))
+	remotes, err := fetchAllCustomers(cmd.Context(), client)
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	plan := planSync(locals, remotes)
+	for _, c := range plan.skip {
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("Skipping %s: it has no email, so it can't be created in Stripe. Add one with 'lane clients edit %s --email <email>'.", c.Alias, c.Alias)))
+	}
+	if len(plan.push)+len(plan.pull)+len(plan.link)+len(plan.conflicts) == 0 {
+		fmt.Println(ui.FormatSuccess("Nothing to sync"))
+		return nil
+	}
+
+	printSyncPlan(plan)
+	if syncDryRun {
+		fmt.Println(ui.Subtle.Render("Dry run: nothing was changed."))
+		return nil
+	}
+
+	// Work on a copy of the address book keyed by alias
+	book := make(map[string]config.Client, len(locals))
+	for _, c := range locals {
+		book[c.Alias] = c
+	}
+
+	var failures []string
+
+	for _, c := range plan.push {
+		created, err := client.UpsertCustomerContext(cmd.Context(), customerFromClient(c))
+		if cancelled(err) {
+			reportCancelled(cmd, "Sync cancelled. The address book was not changed; run it again to pick up where it stopped.")
+			return err
+		}
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", c.Alias, err))
+			continue
+		}
+		c.CustomerID = created.ID
+		book[c.Alias] = c
+		fmt.Println(ui.FormatSuccess("Created " + c.Name + " in Stripe"))
+	}
+
+	for _, cus := range plan.pull {
+		c := clientFromCustomer(cus, book)
+		book[c.Alias] = c
+		fmt.Println(ui.FormatSuccess("Added " + c.Name + " as " + c.Alias))
+	}
+
+	for _, pair := range plan.link {
+		c := pair.local
+		c.CustomerID = pair.remote.ID
+		book[c.Alias] = c
+	}
+
+	for _, pair := range plan.conflicts {
+		side := syncPrefer
+		if side == "" {
+			fmt.Println()
+			fmt.Println(formatConflict(pair))
+			var err error
+			side, err = choose("Keep which version?", []string{"local", "remote", "skip"})
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", pair.local.Alias, err))
+				continue
+			}
+		}
+
+		switch side {
+		case "local":
+			cus := customerFromClient(pair.local)
+			cus.ID = pair.remote.ID
+			_, err := client.UpsertCustomerContext(cmd.Context(), cus)
+			if cancelled(err) {
+				reportCancelled(cmd, "Sync cancelled. The address book was not changed; run it again to pick up where it stopped.")
+				return err
+			}
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", pair.local.Alias, err))
+				continue
+			}
+			c := pair.local
+			c.CustomerID = pair.remote.ID
+			book[c.Alias] = c
+			fmt.Println(ui.FormatSuccess("Updated " + pair.remote.Name + " in Stripe"))
+		case "remote":
+			book[pair.local.Alias] = mergeCustomer(pair.local, pair.remote)
+			fmt.Println(ui.FormatSuccess("Updated " + pair.local.Alias + " from Stripe"))
+		default:
+			fmt.Println(ui.Subtle.Render("Skipped " + pair.local.Alias))
+		}
+	}
+
+	synced := make([]config.Client, 0, len(book))
+	for _, c := range book {
+		synced = append(synced, c)
+	}
+	if err := config.SaveClients(synced); err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	if len(failures) > 0 {
+		for _, f := range failures {
+			fmt.Println(ui.FormatError(f))
+		}
+		return withExitCode(exitPartial, fmt.Errorf("%d of %d changes failed", len(failures), len(plan.push)+len(plan.conflicts)))
+	}
+
+	fmt.Println(ui.FormatSuccess("Address book in sync"))
+	return nil
+}
+
+// fetchAllCustomers pages through every remote customer
+func fetchAllCustomers(ctx context.Context, client *api.Client) ([]api.Customer, error) {
+	var all []api.Customer
+	cursor := ""
+	for {
+		page, err := client.ListCustomersContext(ctx, 100, cursor)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Data...)
+		if !page.HasMore || page.NextCursor == "" {
+			return all, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// planSync matches clients to customers by linked ID, then by email.
+// Unmatched clients without an email are skipped rather than created,
+// since a customer without one can't be matched again or invoiced.
+func planSync(locals []config.Client, remotes []api.Customer) syncPlan {
+	var plan syncPlan
+	matched := make(map[string]bool, len(remotes))
+
+	for _, local := range locals {
+		remote, ok := matchCustomer(local, remotes, matched)
+		if !ok {
+			if primaryEmail(local) == "" {
+				plan.skip = append(plan.skip, local)
+				continue
+			}
+			// A linked customer that no longer exists is created afresh
+			local.CustomerID = ""
+			plan.push = append(plan.push, local)
+			continue
+		}
+		matched[remote.ID] = true
+
+		pair := syncPair{local: local, remote: remote, diffs: diffClient(local, remote)}
+		if len(pair.diffs) > 0 {
+			plan.conflicts = append(plan.conflicts, pair)
+		} else if local.CustomerID != remote.ID {
+			plan.link = append(plan.link, pair)
+		}
+	}
+
+	for _, remote := range remotes {
+		if !matched[remote.ID] {
+			plan.pull = append(plan.pull, remote)
+		}
+	}
+
+	return plan
+}
+
+// matchCustomer finds the unmatched customer for a client
+func matchCustomer(local config.Client, remotes []api.Customer, matched map[string]bool) (api.Customer, bool) {
+	if local.CustomerID != "" {
+		for _, r := range remotes {
+			if r.ID == local.CustomerID && !matched[r.ID] {
+				return r, true
+			}
+		}
+	}
+	for _, email := range local.Emails {
+		for _, r := range remotes {
+			if !matched[r.ID] && r.Email != "" && strings.EqualFold(r.Email, email) {
+				return r, true
+			}
+		}
+	}
+	return api.Customer{}, false
+}
+
+// diffClient lists the fields where a client and customer disagree.
+// Fields that are empty on the remote side are not treated as conflicts.
+func diffClient(local config.Client, remote api.Customer) []fieldDiff {
+	var diffs []fieldDiff
+	add := func(field, l, r string, ignoreCase bool) {
+		if r == "" || l == r || (ignoreCase && strings.EqualFold(l, r)) {
+			return
+		}
+		diffs = append(diffs, fieldDiff{field: field, local: l, remote: r})
+	}
+
+	add("Name", local.Name, remote.Name, false)
+	add("Email", primaryEmail(local), remote.Email, true)
+	add("Currency", local.Currency, remote.Currency, true)
+	add("Address", local.Address, remote.Address, false)
+	add("Tax ID", local.TaxID, remote.TaxID, false)
+
+	return diffs
+}
+
+// customerFromClient converts an address book entry for the API
+func customerFromClient(c config.Client) api.Customer {
+	return api.Customer{
+		ID:       c.CustomerID,
+		Name:     c.Name,
+		Email:    primaryEmail(c),
+		Currency: c.Currency,
+		Address:  c.Address,
+		TaxID:    c.TaxID,
+	}
+}
+
+// clientFromCustomer creates an address book entry with a unique alias
+func clientFromCustomer(cus api.Customer, book map[string]config.Client) config.Client {
+	base := config.AliasFor(cus.Name)
+	if base == "" {
+		base = config.AliasFor(strings.Split(cus.Email, "@")[0])
+	}
+	if base == "" {
+		base = "client"
+	}
+
+	alias := base
+	for i := 2; ; i++ {
+		if _, taken := book[alias]; !taken {
+			break
+		}
+		alias = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	c := mergeCustomer(config.Client{Alias: alias}, cus)
+	return c
+}
+
+// mergeCustomer overwrites a client's synced fields with the customer's,
+// keeping local-only details such as payment terms and secondary emails
+func mergeCustomer(c config.Client, cus api.Customer) config.Client {
+	c.CustomerID = cus.ID
+	if cus.Name != "" {
+		c.Name = cus.Name
+	}
+	if cus.Email != "" {
+		emails := []string{cus.Email}
+		for _, e := range c.Emails {
+			if !strings.EqualFold(e, cus.Email) {
+				emails = append(emails, e)
+			}
+		}
+		c.Emails = emails
+	}
+	if cus.Currency != "" {
+		c.Currency = strings.ToLower(cus.Currency)
+	}
+	if cus.Address != "" {
+		c.Address = cus.Address
+	}
+	if cus.TaxID != "" {
+		c.TaxID = cus.TaxID
+	}
+	return c
+}
+
+// primaryEmail returns a client's first billing email
+func primaryEmail(c config.Client) string {
+	if len(c.Emails) == 0 {
+		return ""
+	}
+	return c.Emails[0]
+}
+
+// printSyncPlan summarizes what sync is about to do
+func printSyncPlan(plan syncPlan) {
+	fmt.Println()
+	for _, c := range plan.push {
+		fmt.Println(ui.Progress.Render("↑ ") + c.Name + ui.Subtle.Render(" (create in Stripe)"))
+	}
+	for _, cus := range plan.pull {
+		fmt.Println(ui.Progress.Render("↓ ") + cus.Name + ui.Subtle.Render(" (add to address book)"))
+	}
+	for _, pair := range plan.link {
+		fmt.Println(ui.Progress.Render("= ") + pair.local.Name + ui.Subtle.Render(" (link to "+pair.remote.ID+")"))
+	}
+	for _, pair := range plan.conflicts {
+		fmt.Println(ui.ErrorStyle.Render("≠ ") + pair.local.Name + ui.Subtle.Render(fmt.Sprintf(" (%d conflicting fields)", len(pair.diffs))))
+	}
+	fmt.Println()
+}
+
+// formatConflict renders the local and remote versions side by side
+func formatConflict(pair syncPair) string {
+	rows := make([][]string, 0, len(pair.diffs))
+	for _, d := range pair.diffs {
+		rows = append(rows, []string{d.field, d.local, d.remote})
+	}
+	title := ui.Label.Render("Conflict: ") + ui.Value.Render(pair.local.Alias) + ui.Subtle.Render(" ↔ "+pair.remote.ID)
+	return title + "\n" + ui.Table([]string{"Field", "Local", "Stripe"}, rows)
+}
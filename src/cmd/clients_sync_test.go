@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func TestPlanSync(t *testing.T) {
+	locals := []config.Client{
+		{Alias: "acme", Name: "Acme Corp", Emails: []string{"billing@acme.com", "AP@acme.com"}},
+		{Alias: "globex", Name: "Globex", Emails: []string{"ap@globex.com"}, Currency: "usd"},
+		{Alias: "linked", Name: "Initech", Emails: []string{"old@initech.com"}, CustomerID: "cus_init"},
+		{Alias: "synced", Name: "Hooli", Emails: []string{"ap@hooli.com"}, CustomerID: "cus_hooli"},
+		{Alias: "local", Name: "Local Only", Emails: []string{"ap@local.com"}},
+		{Alias: "noemail", Name: "No Email"},
+	}
+	remotes := []api.Customer{
+		{ID: "cus_acme", Name: "Acme Corp", Email: "ap@ACME.com"},
+		{ID: "cus_globex", Name: "Globex Corporation", Email: "ap@globex.com", Currency: "eur"},
+		{ID: "cus_init", Name: "Initech", Email: "new@initech.com"},
+		{ID: "cus_hooli", Name: "Hooli", Email: "ap@hooli.com"},
+		{ID: "cus_new", Name: "Umbrella", Email: "ap@umbrella.com"},
+	}
+
+	plan := planSync(locals, remotes)
+
+	if len(plan.push) != 1 || plan.push[0].Alias != "local" {
+		t.Errorf("push = %+v, want [local]", plan.push)
+	}
+	if len(plan.skip) != 1 || plan.skip[0].Alias != "noemail" {
+		t.Errorf("skip = %+v, want [noemail]", plan.skip)
+	}
+	if len(plan.pull) != 1 || plan.pull[0].ID != "cus_new" {
+		t.Errorf("pull = %+v, want [cus_new]", plan.pull)
+	}
+
+	// acme matches on its secondary email; the primary differs
+	// synced is already linked and identical, so it needs nothing
+	var conflicts []string
+	for _, pair := range plan.conflicts {
+		conflicts = append(conflicts, pair.local.Alias+"="+pair.remote.ID)
+	}
+	want := "acme=cus_acme,globex=cus_globex,linked=cus_init"
+	if got := strings.Join(conflicts, ","); got != want {
+		t.Errorf("conflicts = %s, want %s", got, want)
+	}
+	if len(plan.link) != 0 {
+		t.Errorf("link = %+v, want none", plan.link)
+	}
+
+	globex := plan.conflicts[1].diffs
+	if len(globex) != 2 || globex[0].field != "Name" || globex[1].field != "Currency" {
+		t.Errorf("globex diffs = %+v, want Name and Currency", globex)
+	}
+}
+
+func TestPlanSyncLinksIdentical(t *testing.T) {
+	locals := []config.Client{{Alias: "acme", Name: "Acme Corp", Emails: []string{"ap@acme.com"}, Address: "1 Main St"}}
+	remotes := []api.Customer{{ID: "cus_acme", Name: "Acme Corp", Email: "ap@acme.com"}}
+
+	plan := planSync(locals, remotes)
+	if len(plan.link) != 1 || len(plan.conflicts) != 0 {
+		t.Errorf("plan = %+v, want a single link", plan)
+	}
+}
+
+func TestPlanSyncRecreatesDeletedCustomer(t *testing.T) {
+	locals := []config.Client{{Alias: "acme", Name: "Acme Corp", Emails: []string{"ap@acme.com"}, CustomerID: "cus_deleted"}}
+
+	plan := planSync(locals, nil)
+
+	if len(plan.push) != 1 {
+		t.Fatalf("push = %+v, want acme", plan.push)
+	}
+	if cus := customerFromClient(plan.push[0]); cus.ID != "" {
+		t.Errorf("pushed customer ID = %q, want empty so it is created", cus.ID)
+	}
+}
+
+func TestRunClientsSyncCancelled(t *testing.T) {
+	setTempHome(t)
+	if err := config.SaveClients([]config.Client{
+		{Alias: "acme", Name: "Acme Corp", Emails: []string{"ap@acme.com"}},
+		{Alias: "globex", Name: "Globex", Emails: []string{"ap@globex.com"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var pushes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			json.NewEncoder(w).Encode(api.CustomerList{})
+			return
+		}
+		// Ctrl-C while the first client is being created
+		pushes++
+		io.Copy(io.Discard, r.Body)
+		cancel()
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+	t.Setenv(config.EnvAPIURL, server.URL)
+	t.Setenv(config.EnvAuthToken, "test_token")
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	err := runClientsSync(cmd, nil)
+	if got := ExitCode(err); got != exitCancelled {
+		t.Errorf("runClientsSync() error = %v (exit %d), want exit %d", err, got, exitCancelled)
+	}
+	if pushes != 1 {
+		t.Errorf("runClientsSync() made %d pushes after Ctrl-C, want it to stop at the first", pushes)
+	}
+}
+
+func TestClientFromCustomer(t *testing.T) {
+	book := map[string]config.Client{"acme-corp": {Alias: "acme-corp"}}
+
+	c := clientFromCustomer(api.Customer{ID: "cus_1", Name: "Acme Corp.", Email: "ap@acme.com", Currency: "EUR"}, book)
+	if c.Alias != "acme-corp-2" || c.CustomerID != "cus_1" || c.Currency != "eur" {
+		t.Errorf("unexpected client: %+v", c)
+	}
+	if len(c.Emails) != 1 || c.Emails[0] != "ap@acme.com" {
+		t.Errorf("Emails = %v, want [ap@acme.com]", c.Emails)
+	}
+
+	c = clientFromCustomer(api.Customer{ID: "cus_2", Email: "jane.doe@example.com"}, book)
+	if c.Alias != "jane-doe" {
+		t.Errorf("Alias = %q, want jane-doe", c.Alias)
+	}
+}
+
+func TestMergeCustomer(t *testing.T) {
+	terms := 30
+	local := config.Client{Alias: "acme", Name: "Acme", Emails: []string{"old@acme.com", "cfo@acme.com"}, Terms: &terms}
+	got := mergeCustomer(local, api.Customer{ID: "cus_1", Name: "Acme Corp", Email: "cfo@acme.com"})
+
+	if got.Name != "Acme Corp" || got.CustomerID != "cus_1" || got.Terms == nil {
+		t.Errorf("unexpected client: %+v", got)
+	}
+	if strings.Join(got.Emails, ",") != "cfo@acme.com,old@acme.com" {
+		t.Errorf("Emails = %v, want remote email first without duplicates", got.Emails)
+	}
+}
+
+func TestChoose(t *testing.T) {
+	options := []string{"local", "remote", "skip"}
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"l\n", "local"},
+		{"REMOTE\n", "remote"},
+		{"x\nr\n", "remote"},
+	}
+
+	for _, tt := range tests {
+		setStdin(t, tt.input)
+		if got, err := choose("Keep?", options); err != nil || got != tt.want {
+			t.Errorf("choose() with input %q = %q, %v, want %q", tt.input, got, err, tt.want)
+		}
+	}
+
+	setStdin(t, "")
+	if got, err := choose("Keep?", options); err == nil {
+		t.Errorf("choose() with no input = %q, want an error", got)
+	}
+}
+
+func TestPromptsSharePipedInput(t *testing.T) {
+	setStdin(t, "l\nr\ny\n")
+
+	options := []string{"local", "remote", "skip"}
+	for _, want := range []string{"local", "remote"} {
+		if got, err := choose("Keep?", options); err != nil || got != want {
+			t.Errorf("choose() = %q, %v, want %q", got, err, want)
+		}
+	}
+	if err := confirm("Continue?"); err != nil {
+		t.Errorf("confirm() error = %v, want the third piped answer", err)
+	}
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// newClientFlagsCmd returns a fresh command bound to the address book flags
+func newClientFlagsCmd(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringVar(&bookName, "name", "", "")
+	cmd.Flags().StringArrayVar(&bookEmails, "email", nil, "")
+	cmd.Flags().StringVar(&bookCurrency, "currency", "", "")
+	cmd.Flags().StringVar(&bookTerms, "terms", "", "")
+	cmd.Flags().StringVar(&bookAddress, "address", "", "")
+	cmd.Flags().StringVar(&bookTaxID, "tax-id", "", "")
+
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	return cmd
+}
+
+func TestApplyClientFlags(t *testing.T) {
+	t.Run("sets passed fields", func(t *testing.T) {
+		entry := config.Client{Alias: "acme", Name: "Acme Corp", TaxID: "OLD"}
+		cmd := newClientFlagsCmd(t, "--email", "ap@acme.com", "--email", "cfo@acme.com", "--currency", "EUR", "--terms", "30")
+		if err := applyClientFlags(cmd, &entry); err != nil {
+			t.Fatalf("applyClientFlags() error = %v", err)
+		}
+		if len(entry.Emails) != 2 || entry.Currency != "eur" || entry.Terms == nil || *entry.Terms != 30 {
+			t.Errorf("unexpected entry: %+v", entry)
+		}
+		if entry.TaxID != "OLD" {
+			t.Errorf("TaxID = %q, want it left unchanged", entry.TaxID)
+		}
+	})
+
+	t.Run("clears terms", func(t *testing.T) {
+		days := 14
+		entry := config.Client{Alias: "acme", Terms: &days}
+		cmd := newClientFlagsCmd(t, "--terms", "")
+		if err := applyClientFlags(cmd, &entry); err != nil {
+			t.Fatalf("applyClientFlags() error = %v", err)
+		}
+		if entry.Terms != nil {
+			t.Errorf("Terms = %v, want nil", *entry.Terms)
+		}
+	})
+
+	errorCases := [][]string{
+		{"--email", "not-an-email"},
+		{"--currency", "xyz"},
+		{"--terms", "soon"},
+		{"--terms", "-5"},
+	}
+	for _, args := range errorCases {
+		t.Run(args[0]+" "+args[1], func(t *testing.T) {
+			entry := config.Client{Alias: "acme"}
+			if err := applyClientFlags(newClientFlagsCmd(t, args...), &entry); err == nil {
+				t.Errorf("applyClientFlags(%v) expected error", args)
+			}
+		})
+	}
+}
+
+func TestApplyClient(t *testing.T) {
+	entry := &config.Client{
+		Alias:      "acme",
+		Name:       "Acme Corp",
+		Emails:     []string{"ap@acme.com", "cfo@acme.com"},
+		Address:    "1 Main St",
+		TaxID:      "DE123456789",
+		CustomerID: "cus_123",
+	}
+
+	req := api.InvoiceRequest{ClientName: "acme"}
+	applyClient(&req, entry, false)
+	if req.ClientName != "Acme Corp" || req.ClientEmail != "ap@acme.com" {
+		t.Errorf("unexpected client fields: %+v", req)
+	}
+	if len(req.CCEmails) != 1 || req.CCEmails[0] != "cfo@acme.com" {
+		t.Errorf("CCEmails = %v, want [cfo@acme.com]", req.CCEmails)
+	}
+	if req.ClientAddress != "1 Main St" || req.ClientTaxID != "DE123456789" {
+		t.Errorf("address and tax ID not applied: %+v", req)
+	}
+	if req.CustomerID != "cus_123" {
+		t.Errorf("CustomerID = %q, want cus_123", req.CustomerID)
+	}
+
+	// An explicit --email wins over the address book
+	req = api.InvoiceRequest{ClientName: "acme", ClientEmail: "me@example.com"}
+	applyClient(&req, entry, true)
+	if req.ClientEmail != "me@example.com" || req.CCEmails != nil {
+		t.Errorf("explicit email was overridden: %+v", req)
+	}
+}
+
+func TestParseManifestWithAlias(t *testing.T) {
+	setTempHome(t)
+
+	terms := 30
+	if err := config.AddClient(config.Client{
+		Alias:    "acme",
+		Name:     "Acme Corp",
+		Emails:   []string{"ap@acme.com"},
+		Currency: "eur",
+		Terms:    &terms,
+	}); err != nil {
+		t.Fatalf("AddClient() error = %v", err)
+	}
+
+	req, cur, err := parseManifest([]byte("client: acme\nsend: true\nitems:\n  - description: A\n    unit_price: 10\n"))
+	if err != nil {
+		t.Fatalf("parseManifest() error = %v", err)
+	}
+	if req.ClientName != "Acme Corp" || req.ClientEmail != "ap@acme.com" {
+		t.Errorf("alias not applied: %+v", req)
+	}
+	if cur.Code != "EUR" {
+		t.Errorf("currency = %s, want the client's EUR", cur.Code)
+	}
+	if req.DueDate == nil {
+		t.Error("DueDate should come from the client's net 30 terms")
+	}
+
+	// Manifest values take precedence
+	req, cur, err = parseManifest([]byte("client: acme\ncurrency: usd\nitems:\n  - description: A\n    unit_price: 10\n"))
+	if err != nil {
+		t.Fatalf("parseManifest() error = %v", err)
+	}
+	if cur.Code != "USD" || req.Currency != "usd" {
+		t.Errorf("currency = %s, want the manifest's USD", cur.Code)
+	}
+}
+
+func TestEditClientTerms(t *testing.T) {
+	setTempHome(t)
+	if err := config.AddClient(config.Client{Alias: "acme", Name: "Acme Corp"}); err != nil {
+		t.Fatalf("AddClient() error = %v", err)
+	}
+
+	if err := editClientTerms("acme", "30"); err != nil {
+		t.Fatalf("editClientTerms() error = %v", err)
+	}
+	entry, _ := config.FindClient("acme")
+	if entry == nil || entry.Terms == nil || *entry.Terms != 30 {
+		t.Fatalf("entry = %+v, want net 30", entry)
+	}
+
+	if err := editClientTerms("acme", ""); err != nil {
+		t.Fatalf("editClientTerms() error = %v", err)
+	}
+	if entry, _ = config.FindClient("acme"); entry.Terms != nil {
+		t.Errorf("Terms = %d after unset, want none", *entry.Terms)
+	}
+
+	if err := editClientTerms("acme", "soon"); ExitCode(err) != exitUsage {
+		t.Errorf("editClientTerms() with bad days exit code = %d, want %d", ExitCode(err), exitUsage)
+	}
+	if err := editClientTerms("globex", "30"); err == nil {
+		t.Error("editClientTerms() expected error for a client not in the address book")
+	}
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/forrestcai35/lane/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// showOrigin adds the Origin column to 'lane config list'
+var showOrigin bool
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Get and set persistent settings",
+	Long: `Get and set the settings kept in config.toml. The file lives in
+$XDG_CONFIG_HOME/lane when XDG_CONFIG_HOME is set, otherwise in ~/.lane.
+
+Settings resolve from, highest precedence first: command-line flags,
+environment variables, the project's .lane.yaml, the profile, config.toml,
+then the default.`,
+	Example: `  lane config set currency eur
+  lane config set send true
+  lane config list --show-origin`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:               "get <key>",
+	Short:             "Print a setting's value",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSettingKey,
+	RunE:              runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:               "set <key> <value>",
+	Short:             "Save a setting in config.toml",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeSettingKey,
+	RunE:              runConfigSet,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:               "unset <key>",
+	Short:             "Remove a setting from config.toml",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSettingKey,
+	RunE:              runConfigUnset,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every setting with its current value",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigList,
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open config.toml in $VISUAL or $EDITOR",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigEdit,
+}
+
+func init() {
+	configListCmd.Flags().BoolVar(&showOrigin, "show-origin", false, "Show where each value comes from")
+
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configEditCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// isConfigCmd reports whether cmd is 'lane config' or one of its subcommands
+func isConfigCmd(cmd *cobra.Command) bool {
+	return cmd == configCmd || cmd.Parent() == configCmd
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	v, err := config.GetSetting(args[0])
+	if err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	fmt.Println(v.Value)
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	if err := config.SetUserSetting(args[0], args[1]); err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	fmt.Println(ui.FormatSuccess(fmt.Sprintf("Set %s to %s", args[0], args[1])))
+	warnShadowed(args[0])
+	return nil
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	if err := config.UnsetUserSetting(args[0]); err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	fmt.Println(ui.FormatSuccess("Unset " + args[0]))
+	return nil
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	values, err := config.ListSettings()
+	if err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	fmt.Println(formatSettings(values, showOrigin))
+
+	project, err := config.FindProject()
+	if err == nil && project != nil {
+		fmt.Println(ui.FormatLabel("Project", project.Path))
+	}
+	return nil
+}
+
+func runConfigEdit(cmd *cobra.Command, args []string) error {
+	path, err := config.EnsureUserConfig()
+	if err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	editor := exec.Command("sh", "-c", editorCommand()+` "$1"`, "sh", path)
+	if runtime.GOOS == "windows" {
+		editor = exec.Command(editorCommand(), path)
+	}
+	editor.Stdin = os.Stdin
+	editor.Stdout = os.Stdout
+	editor.Stderr = os.Stderr
+	if err := editor.Run(); err != nil {
+		err = fmt.Errorf("editor failed: %w", err)
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	// Catch typos now rather than on the next command
+	if err := config.CheckSettings(); err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+	return nil
+}
+
+// formatSettings renders resolved settings as a table
+func formatSettings(values []config.Value, origin bool) string {
+	headers := []string{"Key", "Value"}
+	if origin {
+		headers = append(headers, "Origin")
+	}
+
+	rows := make([][]string, 0, len(values))
+	for _, v := range values {
+		row := []string{v.Key, v.Value}
+		if origin {
+			row = append(row, v.Origin)
+		}
+		rows = append(rows, row)
+	}
+	return ui.Table(headers, rows)
+}
+
+// warnShadowed points out when a value just saved is overridden by an env
+// var or the profile, so 'lane config set' doesn't seem to do nothing
+func warnShadowed(key string) {
+	v, err := config.GetSetting(key)
+	if err != nil {
+		return
+	}
+	path, _ := config.UserConfigPath()
+	if v.Origin != path {
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("Note: %s is overridden by %s", key, v.Origin)))
+	}
+}
+
+// editorCommand returns $VISUAL, then $EDITOR, then the platform default
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(env); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// completeSettingKey offers setting keys for the first argument
+func completeSettingKey(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var keys []string
+	for _, key := range config.SettingKeys() {
+		s, _ := config.LookupSetting(key)
+		keys = append(keys, key+"\t"+s.Description)
+	}
+	return keys, cobra.ShellCompDirectiveNoFileComp
+}
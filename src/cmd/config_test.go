@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forrestcai35/lane/internal/config"
+)
+
+func TestFormatSettings(t *testing.T) {
+	values := []config.Value{
+		{Key: "currency", Value: "eur", Origin: "env LANE_CURRENCY"},
+		{Key: "send", Value: "false", Origin: "default"},
+	}
+
+	out := formatSettings(values, false)
+	if !strings.Contains(out, "currency") || !strings.Contains(out, "eur") {
+		t.Errorf("missing setting in output:\n%s", out)
+	}
+	if strings.Contains(out, "Origin") || strings.Contains(out, "LANE_CURRENCY") {
+		t.Errorf("origins shown without --show-origin:\n%s", out)
+	}
+
+	out = formatSettings(values, true)
+	if !strings.Contains(out, "Origin") || !strings.Contains(out, "env LANE_CURRENCY") {
+		t.Errorf("origins missing with --show-origin:\n%s", out)
+	}
+}
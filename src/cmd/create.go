@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/forrestcai35/lane/internal/currency"
+	"github.com/forrestcai35/lane/internal/ui"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	manifestFile string
+	createNoCopy bool
+	createDraft  bool
+)
+
+var createCmd = &cobra.Command{
+	Use:   "create -f <file>",
+	Short: "Create an itemized invoice from a manifest file",
+	Long: `Create an invoice from a YAML (or JSON) manifest.
+
+Example manifest:
+
+  client: Acme Corp  # or an address book alias
+  email: ap@acme.com
+  currency: eur
+  description: March retainer
+  send: true        # or draft: true to review before sending
+  due: +14d         # or net: 30
+  items:
+    - description: Design
+      quantity: 3
+      unit_price: 150
+      tax_rate: 20
+    - description: Hosting
+      unit_price: 20`,
+	Example: `  lane create -f invoice.yaml
+  lane create -f invoice.yaml --no-copy`,
+	Args: cobra.NoArgs,
+	RunE: runCreate,
+}
+
+func init() {
+	createCmd.Flags().StringVarP(&manifestFile, "file", "f", "", "Path to the invoice manifest (required)")
+	createCmd.Flags().BoolVar(&createNoCopy, "no-copy", false, "Don't copy link to clipboard")
+	createCmd.Flags().BoolVar(&createDraft, "draft", false, "Create a draft for review instead of finalizing")
+	createCmd.MarkFlagRequired("file")
+
+	rootCmd.AddCommand(createCmd)
+}
+
+// invoiceManifest is the file format read by 'lane create -f'
+type invoiceManifest struct {
+	Client      string         `yaml:"client"`
+	Email       string         `yaml:"email"`
+	Currency    string         `yaml:"currency"`
+	Locale      string         `yaml:"locale"` // Number format for unit prices (default: the locale setting)
+	Description string         `yaml:"description"`
+	Send        bool           `yaml:"send"`
+	Draft       bool           `yaml:"draft"`
+	Due         string         `yaml:"due"` // Same forms as --due
+	Net         *int           `yaml:"net"` // Payment terms in days
+	Items       []manifestItem `yaml:"items"`
+}
+
+// manifestItem is a single line item in a manifest
+type manifestItem struct {
+	Description string `yaml:"description"`
+	Quantity    *int64 `yaml:"quantity"` // Defaults to 1
+	UnitPrice   string `yaml:"unit_price"`
+	TaxRate     string `yaml:"tax_rate"` // Percent, e.g. 20 or "20%"
+}
+
+func runCreate(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(manifestFile)
+	if err != nil {
+		err = fmt.Errorf("could not read manifest: %w", err)
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	req, cur, err := parseManifest(data)
+	if err == nil && createDraft {
+		req.Draft = true
+		if req.SendEmail {
+			err = fmt.Errorf("send can't be used with --draft")
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("%s: %w", manifestFile, err)
+		fmt.Println(ui.FormatError(err.Error()))
+		return usageError(err)
+	}
+
+	return createInvoice(cmd, req, cur, copyEnabled(createNoCopy))
+}
+
+// parseManifest decodes a manifest into an invoice request
+func parseManifest(data []byte) (api.InvoiceRequest, currency.Currency, error) {
+	var m invoiceManifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return api.InvoiceRequest{}, currency.Currency{}, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	// Fill in defaults from the address book; manifest values win
+	var entry *config.Client
+	if m.Client != "" {
+		var err error
+		entry, err = config.FindClient(m.Client)
+		if err != nil {
+			return api.InvoiceRequest{}, currency.Currency{}, err
+		}
+	}
+	if entry != nil {
+		if m.Currency == "" {
+			m.Currency = entry.Currency
+		}
+		if m.Net == nil && m.Due == "" {
+			m.Net = entry.Terms
+		}
+	}
+
+	if m.Currency == "" {
+		m.Currency = config.GetDefaultCurrency()
+	}
+	cur, ok := currency.Lookup(m.Currency)
+	if !ok {
+		return api.InvoiceRequest{}, currency.Currency{}, fmt.Errorf("unknown currency: %s", m.Currency)
+	}
+
+	if m.Locale == "" {
+		m.Locale = config.GetLocale()
+	}
+
+	if len(m.Items) == 0 {
+		return api.InvoiceRequest{}, currency.Currency{}, fmt.Errorf("manifest has no items")
+	}
+
+	if m.Send && m.Email == "" && (entry == nil || len(entry.Emails) == 0) {
+		return api.InvoiceRequest{}, currency.Currency{}, fmt.Errorf("send requires an email")
+	}
+
+	if m.Send && m.Draft {
+		return api.InvoiceRequest{}, currency.Currency{}, fmt.Errorf("send can't be used with draft")
+	}
+
+	req := api.InvoiceRequest{
+		Currency:    strings.ToLower(cur.Code),
+		ClientName:  m.Client,
+		ClientEmail: m.Email,
+		Description: m.Description,
+		SendEmail:   m.Send,
+		Draft:       m.Draft,
+	}
+	if entry != nil {
+		applyClient(&req, entry, m.Email != "")
+	}
+
+	for i, mi := range m.Items {
+		quantity := int64(1)
+		if mi.Quantity != nil {
+			quantity = *mi.Quantity
+		}
+		if quantity <= 0 {
+			return api.InvoiceRequest{}, currency.Currency{}, fmt.Errorf("item %d: quantity must be a positive whole number", i+1)
+		}
+
+		item, err := newLineItem(mi.Description, quantity, mi.UnitPrice, mi.TaxRate, cur, m.Locale)
+		if err != nil {
+			return api.InvoiceRequest{}, currency.Currency{}, fmt.Errorf("item %d: %w", i+1, err)
+		}
+		req.LineItems = append(req.LineItems, item)
+	}
+
+	subtotal, tax, err := itemTotals(req.LineItems)
+	if err != nil {
+		return api.InvoiceRequest{}, currency.Currency{}, err
+	}
+	req.Amount = subtotal + tax
+
+	due, err := resolveDueDate(m.Due, m.Net, time.Now())
+	if err != nil {
+		return api.InvoiceRequest{}, currency.Currency{}, err
+	}
+	req.DueDate = due
+
+	return req, cur, nil
+}
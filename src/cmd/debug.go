@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// Flags
+var (
+	debug   bool
+	logFile bool
+)
+
+// debugLog is the open --log-file, closed when the command finishes
+var debugLog *os.File
+
+// setupDebug turns on request tracing for --debug, --log-file or the debug
+// setting. Traces go to stderr, or with --log-file to the log under the
+// config directory.
+func setupDebug(cmd *cobra.Command) error {
+	enabled := config.GetDebug()
+	if cmd.Flags().Changed("debug") {
+		enabled = debug
+	}
+	if !enabled && !logFile {
+		return nil
+	}
+
+	var w io.Writer = os.Stderr
+	if logFile {
+		f, err := config.OpenLog()
+		if err != nil {
+			return err
+		}
+		debugLog = f
+		w = f
+	}
+	api.SetDebugOutput(w)
+	return nil
+}
+
+// closeDebugLog stops tracing and closes the --log-file, if any
+func closeDebugLog() {
+	if debugLog == nil {
+		return
+	}
+	api.SetDebugOutput(nil)
+	debugLog.Close()
+	debugLog = nil
+}
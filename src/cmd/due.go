@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// parseDueDate resolves a due date relative to now, in now's time zone.
+// Accepted forms:
+//
+//	2026-11-30            calendar date
+//	today, tomorrow
+//	+14d, +2w, +1m        days, weeks or months from today ("+" optional)
+//	in 14 days, in 2 weeks
+//	friday, next friday   the next such weekday after today
+//	eom, end of month     last day of the current month
+//
+// The result is the end of that day, so an invoice due "2026-11-30" can
+// be paid any time on the 30th in the client's local time zone.
+func parseDueDate(s string, now time.Time) (time.Time, error) {
+	input := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var day time.Time
+	switch {
+	case input == "":
+		return time.Time{}, fmt.Errorf("due date is empty")
+	case input == "today":
+		day = today
+	case input == "tomorrow":
+		day = today.AddDate(0, 0, 1)
+	case input == "eom" || input == "end of month":
+		day = time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, today.Location())
+	default:
+		var err error
+		day, err = parseDueDay(input, today)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid due date %q (try 2026-11-30, +14d, \"next friday\" or tomorrow)", s)
+		}
+	}
+
+	if day.Before(today) {
+		return time.Time{}, fmt.Errorf("due date %s is in the past", day.Format("2006-01-02"))
+	}
+
+	return endOfDay(day), nil
+}
+
+// parseDueDay handles calendar dates, offsets and weekdays
+func parseDueDay(input string, today time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", input, today.Location()); err == nil {
+		return t, nil
+	}
+
+	// "next friday" or "friday"
+	if wd, ok := weekdays[strings.TrimPrefix(input, "next ")]; ok {
+		days := (int(wd) - int(today.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, days), nil
+	}
+
+	// "in 14 days" or "in 2 weeks"
+	if rest, ok := strings.CutPrefix(input, "in "); ok {
+		n, unit, found := strings.Cut(rest, " ")
+		if !found {
+			return time.Time{}, fmt.Errorf("missing unit")
+		}
+		return addOffset(today, n, strings.TrimSuffix(unit, "s"))
+	}
+
+	// "+14d", "14d", "+2w", "+1m"
+	offset := strings.TrimPrefix(input, "+")
+	if len(offset) >= 2 {
+		return addOffset(today, offset[:len(offset)-1], offset[len(offset)-1:])
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized date")
+}
+
+// addOffset adds n days, weeks or months to a date
+func addOffset(today time.Time, n, unit string) (time.Time, error) {
+	count, err := strconv.Atoi(n)
+	if err != nil || count < 0 {
+		return time.Time{}, fmt.Errorf("invalid count %q", n)
+	}
+
+	switch unit {
+	case "d", "day":
+		return today.AddDate(0, 0, count), nil
+	case "w", "week":
+		return today.AddDate(0, 0, 7*count), nil
+	case "m", "month":
+		return today.AddDate(0, count, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown unit %q", unit)
+	}
+}
+
+// netDueDate returns the due date for net payment terms
+func netDueDate(days int, now time.Time) (time.Time, error) {
+	if days < 0 {
+		return time.Time{}, fmt.Errorf("--net must be zero or more days")
+	}
+	return endOfDay(now.AddDate(0, 0, days)), nil
+}
+
+// endOfDay returns the last second of t's day in t's time zone
+func endOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, t.Location())
+}
+
+// resolveDueDate picks the due date from --due or --net. Callers pass the
+// client's default payment terms from the address book as net when neither
+// flag is given. It returns nil when none apply, leaving the backend default
+// in place.
+func resolveDueDate(due string, net *int, now time.Time) (*time.Time, error) {
+	switch {
+	case due != "" && net != nil:
+		return nil, fmt.Errorf("use either --due or --net, not both")
+	case due != "":
+		t, err := parseDueDate(due, now)
+		if err != nil {
+			return nil, err
+		}
+		return &t, nil
+	case net != nil:
+		t, err := netDueDate(*net, now)
+		if err != nil {
+			return nil, err
+		}
+		return &t, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDueDate(t *testing.T) {
+	// Wednesday, October 14, 2026 at 22:30 in a zone ahead of UTC
+	zone := time.FixedZone("UTC+10", 10*60*60)
+	now := time.Date(2026, 10, 14, 22, 30, 0, 0, zone)
+
+	tests := []struct {
+		input    string
+		expected string // YYYY-MM-DD in zone
+		wantErr  bool
+	}{
+		{"2026-11-30", "2026-11-30", false},
+		{"today", "2026-10-14", false},
+		{"Tomorrow", "2026-10-15", false},
+		{"+14d", "2026-10-28", false},
+		{"14d", "2026-10-28", false},
+		{"+2w", "2026-10-28", false},
+		{"+1m", "2026-11-14", false},
+		{"in 3 days", "2026-10-17", false},
+		{"in 1 week", "2026-10-21", false},
+		{"friday", "2026-10-16", false},
+		{"next friday", "2026-10-16", false},
+		{"next  Wednesday", "2026-10-21", false},
+		{"eom", "2026-10-31", false},
+		{"end of month", "2026-10-31", false},
+		{"2026-10-01", "", true},
+		{"+14x", "", true},
+		{"-3d", "", true},
+		{"someday", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseDueDate(tt.input, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDueDate(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Location() != zone {
+				t.Errorf("parseDueDate(%q) lost the time zone: %v", tt.input, got.Location())
+			}
+			if day := got.Format("2006-01-02"); day != tt.expected {
+				t.Errorf("parseDueDate(%q) = %s, want %s", tt.input, day, tt.expected)
+			}
+			if got.Hour() != 23 || got.Minute() != 59 {
+				t.Errorf("parseDueDate(%q) = %v, want end of day", tt.input, got)
+			}
+		})
+	}
+}
+
+func TestResolveDueDate(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	net := 15
+
+	tests := []struct {
+		name     string
+		due      string
+		net      *int
+		expected string
+		wantErr  bool
+	}{
+		{"due flag", "2026-12-01", nil, "2026-12-01", false},
+		{"net flag", "", &net, "2026-10-29", false},
+		{"neither", "", nil, "", false},
+		{"both flags", "+3d", &net, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveDueDate(tt.due, tt.net, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveDueDate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.expected == "" {
+				if got != nil {
+					t.Errorf("resolveDueDate() = %v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.Format("2006-01-02") != tt.expected {
+				t.Errorf("resolveDueDate() = %v, want %s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/ui"
+)
+
+// paramFlags maps API request fields to the flag that sets them. The
+// address and tax ID only come from the address book.
+var paramFlags = map[string]string{
+	"amount":         "amount",
+	"currency":       "--currency",
+	"client_name":    "--client",
+	"client_email":   "--email",
+	"cc_emails":      "--email",
+	"description":    "--desc",
+	"due_date":       "--due",
+	"send_email":     "--send",
+	"client_address": "lane clients edit <alias> --address",
+	"client_tax_id":  "lane clients edit <alias> --tax-id",
+}
+
+// lineItemParam matches fields of a line item, e.g. "line_items[1].unit_amount"
+var lineItemParam = regexp.MustCompile(`^line_items\[(\d+)\]`)
+
+// formatError renders an error for the terminal. API errors also list the
+// flags at fault and the request ID to quote to support.
+func formatError(err error) string {
+	var apiErr *api.Error
+	if !errors.As(err, &apiErr) {
+		return ui.FormatError(err.Error())
+	}
+
+	var output strings.Builder
+	message := err.Error()
+	if apiErr.Param != "" && len(apiErr.Fields) == 0 {
+		message = describeParam(apiErr.Param) + ": " + message
+	}
+	output.WriteString(ui.FormatError(message))
+
+	for _, f := range apiErr.Fields {
+		output.WriteString("\n  ")
+		output.WriteString(ui.FormatLabel(describeParam(f.Param), f.Message))
+	}
+
+	if apiErr.RequestID != "" {
+		output.WriteString("\n")
+		output.WriteString(ui.Subtle.Render("Request ID: " + apiErr.RequestID + " (include this if you contact support)"))
+	}
+	return output.String()
+}
+
+// describeParam names the flag behind an API request field, falling back
+// to the field itself
+func describeParam(param string) string {
+	if flag, ok := paramFlags[param]; ok {
+		return flag
+	}
+	if m := lineItemParam.FindStringSubmatch(param); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return fmt.Sprintf("--item %d", n+1)
+	}
+	return param
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/forrestcai35/lane/internal/api"
+)
+
+func TestFormatError(t *testing.T) {
+	err := &api.Error{
+		StatusCode: 422,
+		Message:    "The invoice has invalid fields",
+		RequestID:  "req_123",
+		Fields: []api.FieldError{
+			{Param: "client_email", Message: "is not a valid email address"},
+			{Param: "line_items[1].unit_amount", Message: "must be positive"},
+		},
+	}
+
+	out := formatError(err)
+	for _, want := range []string{"The invoice has invalid fields", "--email", "is not a valid email address", "--item 2", "req_123"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("formatError() missing %q:\n%s", want, out)
+		}
+	}
+
+	// A single param is named in the message
+	out = formatError(&api.Error{StatusCode: 400, Message: "must be a future date", Param: "due_date"})
+	if !strings.Contains(out, "--due: must be a future date") {
+		t.Errorf("formatError() = %q, want the --due flag named", out)
+	}
+
+	// The tax ID is fixed in the address book, not with a flag on lane <amount>
+	out = formatError(&api.Error{StatusCode: 400, Message: "is not a valid VAT number", Param: "client_tax_id"})
+	if !strings.Contains(out, "lane clients edit <alias> --tax-id: is not a valid VAT number") {
+		t.Errorf("formatError() = %q, want the clients edit command named", out)
+	}
+
+	out = formatError(errors.New("plain error"))
+	if !strings.Contains(out, "plain error") || strings.Contains(out, "Request ID") {
+		t.Errorf("formatError() = %q for a plain error", out)
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes. They are documented in the README, so scripts can branch on
+// them; don't renumber.
+const (
+	exitFailure          = 1   // Any other failure
+	exitUsage            = 2   // Unknown command, bad flag or invalid argument
+	exitNotAuthenticated = 3   // Not logged in, session expired or login failed
+	exitNetwork          = 4   // The Lane API couldn't be reached
+	exitInvalid          = 5   // The API rejected the request's values
+	exitRateLimited      = 6   // Still rate limited after retrying
+	exitPartial          = 7   // Some changes in a batch failed
+	exitCancelled        = 130 // Stopped with Ctrl-C
+)
+
+// exitError attaches an exit code to an error
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// withExitCode marks err to exit with code
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+// usageError marks err as a mistake in the command line
+func usageError(err error) error {
+	return withExitCode(exitUsage, err)
+}
+
+// markUsageErrors makes the errors cobra raises for a bad command line,
+// such as an unknown flag or the wrong number of arguments, usage errors.
+// Call it once, after every command is registered.
+func markUsageErrors(cmd *cobra.Command) {
+	if !cmd.HasParent() {
+		cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+			return usageError(err)
+		})
+	}
+
+	if args := cmd.Args; args != nil {
+		cmd.Args = func(cmd *cobra.Command, a []string) error {
+			if err := args(cmd, a); err != nil {
+				return usageError(err)
+			}
+			return nil
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		markUsageErrors(sub)
+	}
+}
+
+// ExitCode returns the process exit status for an error from Execute
+func ExitCode(err error) int {
+	var exitErr *exitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		return exitErr.code
+	case cancelled(err):
+		return exitCancelled
+	case errors.Is(err, api.ErrUnauthorized), errors.Is(err, config.ErrNotLoggedIn):
+		return exitNotAuthenticated
+	case errors.Is(err, api.ErrRateLimited):
+		return exitRateLimited
+	case errors.Is(err, api.ErrInvalidRequest):
+		return exitInvalid
+	case isNetworkError(err):
+		return exitNetwork
+	}
+	return exitFailure
+}
+
+// isNetworkError reports whether err comes from failing to reach a server
+func isNetworkError(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && !cancelled(err)
+}
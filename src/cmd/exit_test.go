@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func TestExitCode(t *testing.T) {
+	networkErr := fmt.Errorf("request failed: %w", &url.Error{Op: "Post", URL: "https://example.com", Err: errors.New("connection refused")})
+	cancelErr := fmt.Errorf("request failed: %w", &url.Error{Op: "Post", URL: "https://example.com", Err: context.Canceled})
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, 0},
+		{"other failure", errors.New("boom"), exitFailure},
+		{"usage", usageError(errors.New("unknown currency: xyz")), exitUsage},
+		{"not logged in", config.ErrNotLoggedIn, exitNotAuthenticated},
+		{"session expired", &api.Error{StatusCode: http.StatusUnauthorized}, exitNotAuthenticated},
+		{"network", networkErr, exitNetwork},
+		{"validation", &api.Error{StatusCode: http.StatusUnprocessableEntity, Message: "invalid"}, exitInvalid},
+		{"rate limited", &api.Error{StatusCode: http.StatusTooManyRequests}, exitRateLimited},
+		{"partial", withExitCode(exitPartial, errors.New("1 of 3 changes failed")), exitPartial},
+		{"cancelled", cancelErr, exitCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkUsageErrors(t *testing.T) {
+	newTree := func() *cobra.Command {
+		root := &cobra.Command{Use: "lane", Args: cobra.MaximumNArgs(1), RunE: func(*cobra.Command, []string) error { return nil }}
+		sub := &cobra.Command{Use: "show", Args: cobra.ExactArgs(1), RunE: func(*cobra.Command, []string) error {
+			return errors.New("boom")
+		}}
+		root.AddCommand(sub)
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		markUsageErrors(root)
+		return root
+	}
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"--bogus"}, exitUsage},
+		{[]string{"show", "--bogus"}, exitUsage},
+		{[]string{"show"}, exitUsage},
+		{[]string{"show", "a", "b"}, exitUsage},
+		{[]string{"show", "a"}, exitFailure},
+	}
+
+	for _, tt := range tests {
+		root := newTree()
+		root.SetArgs(tt.args)
+		if got := ExitCode(root.Execute()); got != tt.want {
+			t.Errorf("exit code for %v = %d, want %d", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/zalando/go-keyring"
+)
+
+// TestMain swaps the OS keyring for an in-memory one, so no test can read
+// or overwrite the developer's real token
+func TestMain(m *testing.M) {
+	keyring.MockInit()
+	os.Exit(m.Run())
+}
+
+// setTempHome points HOME at an empty temp directory for the test
+func setTempHome(t *testing.T) string {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(config.EnvXDGConfigHome, "")
+	return home
+}
+
+// setStdin feeds input to prompts as if typed at a terminal
+func setStdin(t *testing.T, input string) {
+	t.Helper()
+
+	originalStdin, originalTerminal := stdin, stdinIsTerminal
+	stdin = strings.NewReader(input)
+	stdinIsTerminal = func() bool { return true }
+	t.Cleanup(func() { stdin, stdinIsTerminal = originalStdin, originalTerminal })
+}
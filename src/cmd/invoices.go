@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/forrestcai35/lane/internal/currency"
+	"github.com/forrestcai35/lane/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Output format for invoice commands
+	outputFormat string
+
+	// List flags
+	listStatus   string
+	listClient   string
+	listCurrency string
+	listSince    string
+	listUntil    string
+	listLimit    int
+	listCursor   string
+	listAll      bool
+)
+
+var invoicesCmd = &cobra.Command{
+	Use:     "invoices",
+	Aliases: []string{"invoice", "inv"},
+	Short:   "View and manage invoices",
+}
+
+var invoicesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List invoices",
+	Long: `List invoices, newest first.
+
+Results are fetched one page at a time. Use --all to fetch every page, or
+--cursor to continue from where a previous listing stopped.`,
+	Example: `  lane invoices list
+  lane invoices list --status open --client "Acme Corp"
+  lane invoices list --since 2026-01-01 --until 2026-03-31 --all
+  lane invoices list --status paid --output json`,
+	Args: cobra.NoArgs,
+	RunE: runInvoicesList,
+}
+
+func init() {
+	invoicesCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "Output format (table, json; default: the output setting)")
+
+	invoicesListCmd.Flags().StringVar(&listStatus, "status", "", "Filter by status ("+strings.Join(api.Statuses, ", ")+")")
+	invoicesListCmd.Flags().StringVarP(&listClient, "client", "c", "", "Filter by client name or email")
+	invoicesListCmd.Flags().StringVar(&listCurrency, "currency", "", "Filter by currency code")
+	invoicesListCmd.Flags().StringVar(&listSince, "since", "", "Only invoices created on or after this date (YYYY-MM-DD)")
+	invoicesListCmd.Flags().StringVar(&listUntil, "until", "", "Only invoices created on or before this date (YYYY-MM-DD)")
+	invoicesListCmd.Flags().IntVar(&listLimit, "limit", 20, "Invoices per page")
+	invoicesListCmd.Flags().StringVar(&listCursor, "cursor", "", "Continue from a previous page")
+	invoicesListCmd.Flags().BoolVar(&listAll, "all", false, "Fetch every page")
+
+	invoicesListCmd.RegisterFlagCompletionFunc("status", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return api.Statuses, cobra.ShellCompDirectiveNoFileComp
+	})
+	invoicesListCmd.RegisterFlagCompletionFunc("currency", completeCurrency)
+
+	invoicesCmd.AddCommand(invoicesListCmd)
+	rootCmd.AddCommand(invoicesCmd)
+}
+
+func runInvoicesList(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(); err != nil {
+		fmt.Println(formatError(err))
+		return usageError(err)
+	}
+
+	params, err := listParams()
+	if err != nil {
+		fmt.Println(formatError(err))
+		return usageError(err)
+	}
+
+	client, err := api.NewClient()
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	// Fetch one page, or every page with --all
+	var list api.InvoiceList
+	for {
+		page, err := client.ListInvoicesContext(cmd.Context(), params)
+		if err != nil {
+			fmt.Println(formatError(err))
+			return err
+		}
+
+		list.Data = append(list.Data, page.Data...)
+		list.HasMore = page.HasMore
+		list.NextCursor = page.NextCursor
+
+		if !listAll || !page.HasMore || page.NextCursor == "" {
+			break
+		}
+		params.Cursor = page.NextCursor
+	}
+
+	if outputFormat == "json" {
+		return printJSON(list)
+	}
+
+	if len(list.Data) == 0 {
+		fmt.Println(ui.Subtle.Render("No invoices found."))
+		return nil
+	}
+
+	rows := make([][]string, 0, len(list.Data))
+	for _, inv := range list.Data {
+		rows = append(rows, []string{
+			inv.ID,
+			inv.ClientName,
+			formatStatus(inv.Status),
+			displayCurrency(inv.Currency).Format(inv.Total),
+			formatDate(inv.DueDate),
+			formatDate(&inv.Created),
+		})
+	}
+
+	fmt.Println()
+	fmt.Println(ui.Table([]string{"Invoice", "Client", "Status", "Total", "Due", "Created"}, rows, 3))
+	fmt.Println()
+
+	if list.HasMore && list.NextCursor != "" {
+		fmt.Println(ui.Subtle.Render("More invoices available. Next page:"))
+		fmt.Println(ui.Label.Render("  lane invoices list --cursor " + list.NextCursor))
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// listParams builds API filters from the list flags
+func listParams() (api.ListInvoicesParams, error) {
+	params := api.ListInvoicesParams{
+		Status: strings.ToLower(listStatus),
+		Client: listClient,
+		Limit:  listLimit,
+		Cursor: listCursor,
+	}
+
+	if params.Status != "" && !isValidStatus(params.Status) {
+		return params, fmt.Errorf("invalid status %q (expected one of: %s)", listStatus, strings.Join(api.Statuses, ", "))
+	}
+
+	if listCurrency != "" {
+		cur, ok := currency.Lookup(listCurrency)
+		if !ok {
+			return params, fmt.Errorf("unknown currency: %s", listCurrency)
+		}
+		params.Currency = strings.ToLower(cur.Code)
+	}
+
+	if listLimit <= 0 {
+		return params, fmt.Errorf("--limit must be greater than zero")
+	}
+
+	if listSince != "" {
+		since, err := time.ParseInLocation("2006-01-02", listSince, time.Local)
+		if err != nil {
+			return params, fmt.Errorf("invalid --since date %q (expected YYYY-MM-DD)", listSince)
+		}
+		params.CreatedAfter = since
+	}
+
+	if listUntil != "" {
+		until, err := time.ParseInLocation("2006-01-02", listUntil, time.Local)
+		if err != nil {
+			return params, fmt.Errorf("invalid --until date %q (expected YYYY-MM-DD)", listUntil)
+		}
+		// --until is inclusive, so stop at the start of the next day
+		params.CreatedBefore = until.AddDate(0, 0, 1)
+	}
+
+	if !params.CreatedAfter.IsZero() && !params.CreatedBefore.IsZero() && !params.CreatedAfter.Before(params.CreatedBefore) {
+		return params, fmt.Errorf("--since must be on or before --until")
+	}
+
+	return params, nil
+}
+
+// isValidStatus reports whether s is a known invoice status
+func isValidStatus(s string) bool {
+	for _, status := range api.Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// validateOutputFormat checks the --output flag, falling back to the output
+// setting when it isn't given
+func validateOutputFormat() error {
+	if outputFormat == "" {
+		outputFormat = config.GetOutputFormat()
+	}
+	switch outputFormat {
+	case "table", "json":
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q (expected table or json)", outputFormat)
+	}
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// formatStatus colors an invoice status
+func formatStatus(status string) string {
+	switch status {
+	case api.StatusPaid:
+		return ui.SuccessStyle.Render(status)
+	case api.StatusOpen:
+		return ui.Highlight.Render(status)
+	case api.StatusVoid, api.StatusUncollectible:
+		return ui.ErrorStyle.Render(status)
+	default:
+		return ui.Label.Render(status)
+	}
+}
+
+// displayCurrency looks up a currency returned by the API, falling back
+// to the raw code with two decimal places if it is missing from the registry
+func displayCurrency(code string) currency.Currency {
+	cur, ok := currency.Lookup(code)
+	if !ok {
+		cur = currency.Currency{Code: strings.ToUpper(code), MinorUnits: 2}
+	}
+	return cur
+}
+
+// formatDate renders an optional timestamp as a local date
+func formatDate(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "—"
+	}
+	return t.Local().Format("Jan 2, 2006")
+}
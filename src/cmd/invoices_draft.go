@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/clipboard"
+	"github.com/forrestcai35/lane/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Edit flags
+	editAmount      string
+	editDescription string
+	editClient      string
+	editEmail       string
+	editItems       []string
+	editLocale      string
+
+	// Send flags
+	sendNoCopy bool
+)
+
+var invoicesSendCmd = &cobra.Command{
+	Use:   "send <id>",
+	Short: "Finalize a draft and email it to the client",
+	Long: `Email an invoice to its client.
+
+Drafts are finalized first. Sending an open invoice emails it again.`,
+	Example: `  lane invoices send in_1Nv0Xa2eZvKYlo2C
+  lane invoices send in_1Nv0Xa2eZvKYlo2C --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInvoicesSend,
+}
+
+var invoicesEditCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Change a draft invoice",
+	Long: `Change the amount, description, client or line items of a draft.
+
+Only the flags you pass are changed. --item replaces every line item.
+A draft with line items can't take --amount; change its items instead.`,
+	Example: `  lane invoices edit in_1Nv0Xa2eZvKYlo2C --amount 1500
+  lane invoices edit in_1Nv0Xa2eZvKYlo2C --desc "Q3 retainer" --email "ap@acme.com"
+  lane invoices edit in_1Nv0Xa2eZvKYlo2C --item "Design:3x150" --item "Hosting:20"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInvoicesEdit,
+}
+
+// Sending is allowed from draft (finalize and send) or open (resend)
+var sendAction = lifecycleAction{
+	verb: "send",
+	done: "sent",
+	from: []string{api.StatusDraft, api.StatusOpen},
+}
+
+// Editing is only allowed on drafts
+var editAction = lifecycleAction{
+	verb: "edit",
+	done: "edited",
+	from: []string{api.StatusDraft},
+}
+
+func init() {
+	invoicesSendCmd.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "Skip the confirmation prompt")
+	invoicesSendCmd.Flags().BoolVar(&sendNoCopy, "no-copy", false, "Don't copy link to clipboard")
+
+	invoicesEditCmd.Flags().StringVar(&editAmount, "amount", "", "New amount")
+	invoicesEditCmd.Flags().StringVarP(&editDescription, "desc", "d", "", "New description")
+	invoicesEditCmd.Flags().StringVarP(&editClient, "client", "c", "", "New client name")
+	invoicesEditCmd.Flags().StringVarP(&editEmail, "email", "e", "", "New client email address")
+	invoicesEditCmd.Flags().StringArrayVarP(&editItems, "item", "i", nil, "Replace line items, DESCRIPTION:[QTYx]PRICE[@TAX%] (repeatable)")
+	invoicesEditCmd.Flags().StringVar(&editLocale, "locale", "", "Number format for amounts (en: 1,234.56, de: 1.234,56; default: the locale setting)")
+	invoicesEditCmd.MarkFlagsMutuallyExclusive("amount", "item")
+
+	invoicesCmd.AddCommand(invoicesSendCmd)
+	invoicesCmd.AddCommand(invoicesEditCmd)
+}
+
+func runInvoicesSend(cmd *cobra.Command, args []string) error {
+	client, err := api.NewClient()
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	invoice, err := client.GetInvoiceContext(cmd.Context(), args[0])
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	if err := checkTransition(sendAction, invoice); err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	if invoice.ClientEmail == "" {
+		err := fmt.Errorf("invoice %s has no client email (add one with 'lane invoices edit %s --email <address>')", invoice.ID, invoice.ID)
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	if !skipConfirm {
+		if invoice.Status == api.StatusDraft {
+			fmt.Println(ui.Subtle.Render("The draft will be finalized and can no longer be edited."))
+		}
+		question := fmt.Sprintf("Email invoice %s (%s) to %s?",
+			invoice.ID, displayCurrency(invoice.Currency).Format(invoice.Total), invoice.ClientEmail)
+		if err := confirm(question); err != nil {
+			fmt.Println(formatError(err))
+			return err
+		}
+	}
+
+	fmt.Println(ui.FormatStep("Sending invoice..."))
+	result, err := client.SendInvoiceContext(cmd.Context(), invoice.ID)
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	// Copy to clipboard
+	clipboardStatus := ""
+	if copyEnabled(sendNoCopy) && result.PaymentLink != "" && clipboard.IsSupported() {
+		if err := clipboard.Copy(result.PaymentLink); err != nil {
+			clipboardStatus = ui.Subtle.Render("(clipboard unavailable)")
+		} else {
+			clipboardStatus = ui.SuccessStyle.Render("(copied!)")
+		}
+	}
+
+	var output strings.Builder
+	output.WriteString(ui.FormatSuccess("Invoice sent to " + invoice.ClientEmail))
+	output.WriteString("\n\n")
+	output.WriteString(ui.FormatLabel("Invoice", result.ID))
+	if result.PaymentLink != "" {
+		output.WriteString("\n\n")
+		output.WriteString(ui.Label.Render("Payment Link: "))
+		output.WriteString(clipboardStatus)
+		output.WriteString("\n")
+		output.WriteString(ui.FormatLink(result.PaymentLink))
+	}
+
+	fmt.Println(ui.ResultBox.Render(output.String()))
+	fmt.Println()
+
+	return nil
+}
+
+func runInvoicesEdit(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	if !flags.Changed("amount") && !flags.Changed("desc") && !flags.Changed("client") &&
+		!flags.Changed("email") && !flags.Changed("item") {
+		err := fmt.Errorf("nothing to change (use --amount, --desc, --client, --email or --item)")
+		fmt.Println(formatError(err))
+		return usageError(err)
+	}
+
+	client, err := api.NewClient()
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	invoice, err := client.GetInvoiceContext(cmd.Context(), args[0])
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	if err := checkTransition(editAction, invoice); err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	update, err := buildInvoiceUpdate(cmd, invoice)
+	if err != nil {
+		fmt.Println(formatError(err))
+		return usageError(err)
+	}
+
+	updated, err := client.UpdateInvoiceContext(cmd.Context(), invoice.ID, update)
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(ui.FormatSuccess("Draft updated"))
+	fmt.Println(ui.ResultBox.Render(formatInvoice(updated)))
+	fmt.Println()
+
+	return nil
+}
+
+// buildInvoiceUpdate turns the edit flags that were passed into an update.
+// Amounts are parsed in the invoice's own currency.
+func buildInvoiceUpdate(cmd *cobra.Command, invoice *api.Invoice) (api.InvoiceUpdate, error) {
+	var update api.InvoiceUpdate
+	flags := cmd.Flags()
+	cur := displayCurrency(invoice.Currency)
+
+	if flags.Changed("amount") {
+		if len(invoice.LineItems) > 0 {
+			return update, fmt.Errorf("invoice %s has line items, so its amount comes from them (replace them with --item instead of --amount)", invoice.ID)
+		}
+		amount, err := parseAmount(editAmount, cur, amountLocale(editLocale))
+		if err != nil {
+			return update, err
+		}
+		update.Amount = &amount
+	}
+
+	if flags.Changed("item") {
+		for _, spec := range editItems {
+			item, err := parseItem(spec, cur, amountLocale(editLocale))
+			if err != nil {
+				return update, err
+			}
+			update.LineItems = append(update.LineItems, item)
+		}
+		subtotal, tax, err := itemTotals(update.LineItems)
+		if err != nil {
+			return update, err
+		}
+		total := subtotal + tax
+		update.Amount = &total
+	}
+
+	if flags.Changed("desc") {
+		update.Description = &editDescription
+	}
+	if flags.Changed("client") {
+		update.ClientName = &editClient
+	}
+	if flags.Changed("email") {
+		update.ClientEmail = &editEmail
+	}
+
+	return update, nil
+}
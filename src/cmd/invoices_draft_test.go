@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// newEditCmd returns a fresh command bound to the edit flag variables
+func newEditCmd(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringVar(&editAmount, "amount", "", "")
+	cmd.Flags().StringVarP(&editDescription, "desc", "d", "", "")
+	cmd.Flags().StringVarP(&editClient, "client", "c", "", "")
+	cmd.Flags().StringVarP(&editEmail, "email", "e", "", "")
+	cmd.Flags().StringArrayVarP(&editItems, "item", "i", nil, "")
+	cmd.Flags().StringVar(&editLocale, "locale", "en", "")
+
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	return cmd
+}
+
+func TestBuildInvoiceUpdate(t *testing.T) {
+	invoice := &api.Invoice{ID: "inv_1", Status: api.StatusDraft, Currency: "kwd"}
+
+	t.Run("only changed fields", func(t *testing.T) {
+		cmd := newEditCmd(t, "--desc", "Revised", "--amount", "1.250")
+		update, err := buildInvoiceUpdate(cmd, invoice)
+		if err != nil {
+			t.Fatalf("buildInvoiceUpdate() error = %v", err)
+		}
+		if update.Amount == nil || *update.Amount != 1250 {
+			t.Errorf("Amount = %v, want 1250 fils", update.Amount)
+		}
+		if update.Description == nil || *update.Description != "Revised" {
+			t.Errorf("Description = %v, want Revised", update.Description)
+		}
+		if update.ClientName != nil || update.ClientEmail != nil || update.LineItems != nil {
+			t.Errorf("unchanged fields should be nil: %+v", update)
+		}
+	})
+
+	t.Run("items replace amount", func(t *testing.T) {
+		cmd := newEditCmd(t, "--item", "Design:2x1.500", "--item", "Hosting:0.250")
+		update, err := buildInvoiceUpdate(cmd, invoice)
+		if err != nil {
+			t.Fatalf("buildInvoiceUpdate() error = %v", err)
+		}
+		if len(update.LineItems) != 2 {
+			t.Fatalf("got %d line items, want 2", len(update.LineItems))
+		}
+		if update.Amount == nil || *update.Amount != 3250 {
+			t.Errorf("Amount = %v, want 3250", update.Amount)
+		}
+	})
+
+	t.Run("clear email", func(t *testing.T) {
+		cmd := newEditCmd(t, "--email", "")
+		update, err := buildInvoiceUpdate(cmd, invoice)
+		if err != nil {
+			t.Fatalf("buildInvoiceUpdate() error = %v", err)
+		}
+		if update.ClientEmail == nil || *update.ClientEmail != "" {
+			t.Errorf("ClientEmail = %v, want empty string", update.ClientEmail)
+		}
+	})
+
+	t.Run("amount on an itemized draft", func(t *testing.T) {
+		cmd := newEditCmd(t, "--amount", "1500")
+		itemized := &api.Invoice{ID: "inv_1", Status: api.StatusDraft, Currency: "usd", LineItems: []api.LineItem{{Description: "Design", Quantity: 1, UnitAmount: 50000}}}
+		if _, err := buildInvoiceUpdate(cmd, itemized); err == nil {
+			t.Error("buildInvoiceUpdate() expected error for --amount on a draft with line items")
+		}
+	})
+
+	t.Run("invalid amount", func(t *testing.T) {
+		cmd := newEditCmd(t, "--amount", "1.2505")
+		if _, err := buildInvoiceUpdate(cmd, invoice); err == nil {
+			t.Error("buildInvoiceUpdate() expected error for extra precision")
+		}
+	})
+}
+
+func TestDraftTransitions(t *testing.T) {
+	if err := checkTransition(editAction, &api.Invoice{ID: "inv_1", Status: api.StatusOpen}); err == nil {
+		t.Error("editing an open invoice should fail")
+	}
+	if err := checkTransition(sendAction, &api.Invoice{ID: "inv_1", Status: api.StatusDraft}); err != nil {
+		t.Errorf("sending a draft should be allowed: %v", err)
+	}
+	if err := checkTransition(sendAction, &api.Invoice{ID: "inv_1", Status: api.StatusPaid}); err == nil {
+		t.Error("sending a paid invoice should fail")
+	}
+}
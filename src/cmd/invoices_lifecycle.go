@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// skipConfirm skips confirmation prompts (--yes)
+var skipConfirm bool
+
+// lifecycleAction describes an invoice state transition
+type lifecycleAction struct {
+	use     string
+	short   string
+	verb    string   // Used in prompts and errors, e.g. "void"
+	done    string   // Used in the success message, e.g. "voided"
+	from    []string // Statuses the transition is allowed from
+	warning string   // Shown before the confirmation prompt
+	run     func(ctx context.Context, client *api.Client, id string) error
+}
+
+var lifecycleActions = []lifecycleAction{
+	{
+		use:     "finalize <id>",
+		short:   "Finalize a draft invoice so it can be paid",
+		verb:    "finalize",
+		done:    "finalized",
+		from:    []string{api.StatusDraft},
+		warning: "A finalized invoice can no longer be edited.",
+		run: func(ctx context.Context, client *api.Client, id string) error {
+			_, err := client.FinalizeInvoiceContext(ctx, id)
+			return err
+		},
+	},
+	{
+		use:     "void <id>",
+		short:   "Void an invoice that was issued by mistake",
+		verb:    "void",
+		done:    "voided",
+		from:    []string{api.StatusOpen, api.StatusUncollectible},
+		warning: "Voiding is permanent. The client will no longer be able to pay it.",
+		run: func(ctx context.Context, client *api.Client, id string) error {
+			_, err := client.VoidInvoiceContext(ctx, id)
+			return err
+		},
+	},
+	{
+		use:     "mark-uncollectible <id>",
+		short:   "Mark an open invoice as unlikely to be paid",
+		verb:    "mark as uncollectible",
+		done:    "marked as uncollectible",
+		from:    []string{api.StatusOpen},
+		warning: "The invoice stays payable but is written off in your reports.",
+		run: func(ctx context.Context, client *api.Client, id string) error {
+			_, err := client.MarkUncollectibleContext(ctx, id)
+			return err
+		},
+	},
+	{
+		use:     "delete <id>",
+		short:   "Delete a draft invoice",
+		verb:    "delete",
+		done:    "deleted",
+		from:    []string{api.StatusDraft},
+		warning: "Deleting is permanent.",
+		run: func(ctx context.Context, client *api.Client, id string) error {
+			return client.DeleteInvoiceContext(ctx, id)
+		},
+	},
+}
+
+func init() {
+	for _, action := range lifecycleActions {
+		action := action
+		cmd := &cobra.Command{
+			Use:   action.use,
+			Short: action.short,
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return runLifecycleAction(cmd.Context(), action, args[0])
+			},
+		}
+		cmd.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "Skip the confirmation prompt")
+		invoicesCmd.AddCommand(cmd)
+	}
+}
+
+func runLifecycleAction(ctx context.Context, action lifecycleAction, id string) error {
+	client, err := api.NewClient()
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	invoice, err := client.GetInvoiceContext(ctx, id)
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	if err := checkTransition(action, invoice); err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	if !skipConfirm {
+		fmt.Println(ui.Subtle.Render(action.warning))
+		question := fmt.Sprintf("%s invoice %s (%s, %s)?",
+			capitalize(action.verb), invoice.ID, describeClient(invoice),
+			displayCurrency(invoice.Currency).Format(invoice.Total))
+		if err := confirm(question); err != nil {
+			fmt.Println(formatError(err))
+			return err
+		}
+	}
+
+	if err := action.run(ctx, client, invoice.ID); err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	fmt.Println(ui.FormatSuccess("Invoice " + invoice.ID + " " + action.done))
+	return nil
+}
+
+// checkTransition returns an error if the invoice's status does not allow the action
+func checkTransition(action lifecycleAction, invoice *api.Invoice) error {
+	for _, status := range action.from {
+		if invoice.Status == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot %s invoice %s: it is %s (only %s invoices can be %s)",
+		action.verb, invoice.ID, invoice.Status, strings.Join(action.from, " or "), action.done)
+}
+
+// describeClient names the client on an invoice for prompts
+func describeClient(invoice *api.Invoice) string {
+	switch {
+	case invoice.ClientName != "":
+		return invoice.ClientName
+	case invoice.ClientEmail != "":
+		return invoice.ClientEmail
+	default:
+		return "no client"
+	}
+}
+
+// capitalize upper-cases the first letter of s
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
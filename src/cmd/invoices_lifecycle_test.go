@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forrestcai35/lane/internal/api"
+)
+
+func TestCheckTransition(t *testing.T) {
+	actions := make(map[string]lifecycleAction)
+	for _, action := range lifecycleActions {
+		actions[strings.Fields(action.use)[0]] = action
+	}
+
+	tests := []struct {
+		action  string
+		status  string
+		wantErr bool
+	}{
+		{"finalize", api.StatusDraft, false},
+		{"finalize", api.StatusOpen, true},
+		{"void", api.StatusOpen, false},
+		{"void", api.StatusUncollectible, false},
+		{"void", api.StatusPaid, true},
+		{"void", api.StatusDraft, true},
+		{"mark-uncollectible", api.StatusOpen, false},
+		{"mark-uncollectible", api.StatusVoid, true},
+		{"delete", api.StatusDraft, false},
+		{"delete", api.StatusOpen, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.action+" "+tt.status, func(t *testing.T) {
+			action, ok := actions[tt.action]
+			if !ok {
+				t.Fatalf("no lifecycle action %q", tt.action)
+			}
+			err := checkTransition(action, &api.Invoice{ID: "inv_1", Status: tt.status})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkTransition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.status) {
+				t.Errorf("error %q should mention the current status", err)
+			}
+		})
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{"y\n", nil},
+		{"YES\n", nil},
+		{"n\n", errDeclined},
+		{"\n", errDeclined},
+		{"", errDeclined},
+		{"yes", nil},
+	}
+
+	for _, tt := range tests {
+		setStdin(t, tt.input)
+		if err := confirm("Continue?"); err != tt.want {
+			t.Errorf("confirm() with input %q error = %v, want %v", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestConfirmWithoutTerminal(t *testing.T) {
+	setStdin(t, "y\n")
+	stdinIsTerminal = func() bool { return false }
+
+	err := confirm("Continue?")
+	if err == nil || ExitCode(err) != exitUsage {
+		t.Errorf("confirm() without a terminal error = %v (exit %d), want a usage error", err, ExitCode(err))
+	}
+}
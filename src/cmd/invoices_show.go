@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var invoicesShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show an invoice with its payment history",
+	Example: `  lane invoices show in_1Nv0Xa2eZvKYlo2C
+  lane invoices show in_1Nv0Xa2eZvKYlo2C --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInvoicesShow,
+}
+
+func init() {
+	invoicesCmd.AddCommand(invoicesShowCmd)
+}
+
+func runInvoicesShow(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(); err != nil {
+		fmt.Println(formatError(err))
+		return usageError(err)
+	}
+
+	client, err := api.NewClient()
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	invoice, err := client.GetInvoiceContext(cmd.Context(), args[0])
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	if outputFormat == "json" {
+		return printJSON(invoice)
+	}
+
+	fmt.Println()
+	fmt.Println(ui.ResultBox.Render(formatInvoice(invoice)))
+	fmt.Println()
+
+	return nil
+}
+
+// formatInvoice renders the full detail view of an invoice
+func formatInvoice(inv *api.Invoice) string {
+	cur := displayCurrency(inv.Currency)
+
+	var output strings.Builder
+
+	// Details
+	title := inv.ID
+	if inv.Number != "" {
+		title += " (" + inv.Number + ")"
+	}
+	output.WriteString(ui.FormatLabel("Invoice", title))
+	output.WriteString("\n")
+	output.WriteString(ui.Label.Render("Status: ") + formatStatus(inv.Status))
+	output.WriteString("\n")
+	if inv.ClientName != "" {
+		output.WriteString(ui.FormatLabel("Client", inv.ClientName))
+		output.WriteString("\n")
+	}
+	if inv.ClientEmail != "" {
+		output.WriteString(ui.FormatLabel("Email", inv.ClientEmail))
+		output.WriteString("\n")
+	}
+	if inv.Description != "" {
+		output.WriteString(ui.FormatLabel("Description", inv.Description))
+		output.WriteString("\n")
+	}
+	output.WriteString(ui.FormatLabel("Due", formatDueDate(inv)))
+	output.WriteString("\n\n")
+
+	// Amounts
+	if len(inv.LineItems) > 0 {
+		output.WriteString(formatInvoiceItems(inv, cur))
+	} else {
+		output.WriteString(ui.Label.Render("Total: ") + ui.FormatAmount(inv.Total, cur))
+	}
+	output.WriteString("\n")
+	output.WriteString(ui.FormatLabel("Paid", cur.Format(inv.AmountPaid)))
+	output.WriteString("\n")
+	output.WriteString(ui.Label.Render("Amount Due: ") + ui.FormatAmount(inv.AmountDue, cur))
+	output.WriteString("\n")
+
+	// Links
+	if inv.PaymentLink != "" {
+		output.WriteString("\n")
+		output.WriteString(ui.Label.Render("Payment Link:"))
+		output.WriteString("\n")
+		output.WriteString(ui.FormatLink(inv.PaymentLink))
+		output.WriteString("\n")
+	}
+	if inv.PDFUrl != "" {
+		output.WriteString("\n")
+		output.WriteString(ui.Label.Render("PDF:"))
+		output.WriteString("\n")
+		output.WriteString(ui.FormatLink(inv.PDFUrl))
+		output.WriteString("\n")
+	}
+
+	// Timeline
+	if len(inv.Events) > 0 {
+		output.WriteString("\n")
+		output.WriteString(ui.Label.Render("Timeline:"))
+		for _, event := range inv.Events {
+			output.WriteString("\n")
+			output.WriteString(formatEvent(event))
+		}
+	}
+
+	return strings.TrimRight(output.String(), "\n")
+}
+
+// formatDueDate renders the due date, flagging open invoices that are overdue
+func formatDueDate(inv *api.Invoice) string {
+	if inv.DueDate == nil || inv.DueDate.IsZero() {
+		return "—"
+	}
+	due := formatDate(inv.DueDate)
+	if inv.Status == api.StatusOpen && inv.DueDate.Before(time.Now()) {
+		due += " " + ui.ErrorStyle.Render("(overdue)")
+	}
+	return due
+}
+
+// formatEvent renders a single timeline entry
+func formatEvent(e api.Event) string {
+	line := ui.Progress.Render("● ") +
+		ui.Label.Render(e.Time.Local().Format("Jan 2, 2006 15:04")) + "  " +
+		ui.Value.Render(eventTitle(e.Type))
+	if e.Detail != "" {
+		line += " " + ui.Subtle.Render(e.Detail)
+	}
+	return line
+}
+
+// eventTitle turns an event type like "payment_failed" into "Payment failed"
+func eventTitle(eventType string) string {
+	return capitalize(strings.ReplaceAll(eventType, "_", " "))
+}
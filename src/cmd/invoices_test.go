@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/forrestcai35/lane/internal/api"
+)
+
+func TestListParams(t *testing.T) {
+	reset := func() {
+		listStatus, listClient, listCurrency = "", "", ""
+		listSince, listUntil, listCursor = "", "", ""
+		listLimit = 20
+	}
+	defer reset()
+
+	t.Run("valid filters", func(t *testing.T) {
+		reset()
+		listStatus = "OPEN"
+		listCurrency = "EUR"
+		listSince = "2026-01-01"
+		listUntil = "2026-01-31"
+
+		params, err := listParams()
+		if err != nil {
+			t.Fatalf("listParams() error = %v", err)
+		}
+		if params.Status != "open" || params.Currency != "eur" {
+			t.Errorf("unexpected params: %+v", params)
+		}
+		wantAfter := time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)
+		wantBefore := time.Date(2026, 2, 1, 0, 0, 0, 0, time.Local)
+		if !params.CreatedAfter.Equal(wantAfter) {
+			t.Errorf("CreatedAfter = %v, want %v", params.CreatedAfter, wantAfter)
+		}
+		if !params.CreatedBefore.Equal(wantBefore) {
+			t.Errorf("CreatedBefore = %v, want %v (until is inclusive)", params.CreatedBefore, wantBefore)
+		}
+	})
+
+	errorCases := []struct {
+		name string
+		set  func()
+	}{
+		{"unknown status", func() { listStatus = "pending" }},
+		{"unknown currency", func() { listCurrency = "xyz" }},
+		{"bad since", func() { listSince = "01/02/2026" }},
+		{"bad until", func() { listUntil = "tomorrow" }},
+		{"inverted range", func() { listSince, listUntil = "2026-02-01", "2026-01-01" }},
+		{"zero limit", func() { listLimit = 0 }},
+	}
+
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			reset()
+			tt.set()
+			if _, err := listParams(); err == nil {
+				t.Error("listParams() expected error")
+			}
+		})
+	}
+}
+
+func TestFormatInvoice(t *testing.T) {
+	due := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	inv := &api.Invoice{
+		ID:          "inv_123",
+		Number:      "LANE-0042",
+		Status:      api.StatusOpen,
+		ClientName:  "Acme Corp",
+		Currency:    "eur",
+		Subtotal:    40500,
+		Tax:         8100,
+		Total:       48600,
+		AmountDue:   28600,
+		AmountPaid:  20000,
+		DueDate:     &due,
+		PaymentLink: "https://pay.stripe.com/inv_123",
+		PDFUrl:      "https://example.com/inv_123.pdf",
+		// The API applied a 10% discount, so its amounts differ from 3 x 150
+		LineItems: []api.LineItem{{Description: "Design", Quantity: 3, UnitAmount: 15000, TaxRate: 20, Amount: 40500, TaxAmount: 8100}},
+		Events: []api.Event{
+			{Type: "created", Time: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)},
+			{Type: "payment_succeeded", Time: time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC), Detail: "€200.00"},
+		},
+	}
+
+	got := formatInvoice(inv)
+	for _, want := range []string{
+		"inv_123 (LANE-0042)", "open", "Acme Corp", "Design",
+		"€405.00", "€81.00", "€486.00", "€200.00", "€286.00", "(overdue)",
+		"https://pay.stripe.com/inv_123", "https://example.com/inv_123.pdf",
+		"Created", "Payment succeeded",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatInvoice() missing %q", want)
+		}
+	}
+	if strings.Contains(got, "€450.00") {
+		t.Error("formatInvoice() recomputed the line amount instead of using the API's")
+	}
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/currency"
+	"github.com/forrestcai35/lane/internal/ui"
+)
+
+// parseItem parses an --item value of the form DESCRIPTION:[QTYx]PRICE[@TAX%],
+// e.g. "Design:3x150", "Hosting:20" or "Consulting:10x95@20%"
+func parseItem(spec string, cur currency.Currency, locale string) (api.LineItem, error) {
+	i := strings.LastIndex(spec, ":")
+	if i < 0 {
+		return api.LineItem{}, fmt.Errorf("invalid item %q: expected DESCRIPTION:[QTYx]PRICE[@TAX%%]", spec)
+	}
+	desc, rest := spec[:i], spec[i+1:]
+
+	price, tax, _ := strings.Cut(rest, "@")
+
+	qty := "1"
+	if j := strings.IndexAny(price, "xX"); j >= 0 {
+		qty, price = price[:j], price[j+1:]
+	}
+
+	quantity, err := strconv.ParseInt(strings.TrimSpace(qty), 10, 64)
+	if err != nil || quantity <= 0 {
+		return api.LineItem{}, fmt.Errorf("invalid item %q: quantity must be a positive whole number", spec)
+	}
+
+	item, err := newLineItem(desc, quantity, price, tax, cur, locale)
+	if err != nil {
+		return api.LineItem{}, fmt.Errorf("invalid item %q: %w", spec, err)
+	}
+	return item, nil
+}
+
+// newLineItem validates and assembles a line item from its parts
+func newLineItem(desc string, quantity int64, price, tax string, cur currency.Currency, locale string) (api.LineItem, error) {
+	desc = strings.TrimSpace(desc)
+	if desc == "" {
+		return api.LineItem{}, fmt.Errorf("description is required")
+	}
+
+	unitAmount, err := parseAmount(price, cur, locale)
+	if err != nil {
+		return api.LineItem{}, err
+	}
+
+	if unitAmount > math.MaxInt64/quantity {
+		return api.LineItem{}, fmt.Errorf("line total is too large")
+	}
+
+	taxRate, err := parseTaxRate(tax, locale)
+	if err != nil {
+		return api.LineItem{}, err
+	}
+
+	return api.LineItem{
+		Description: desc,
+		Quantity:    quantity,
+		UnitAmount:  unitAmount,
+		TaxRate:     taxRate,
+	}, nil
+}
+
+// parseTaxRate parses a percentage such as "20", "20%" or "8.5%" written
+// in the locale's number format. An empty string means no tax.
+func parseTaxRate(s, locale string) (api.Percent, error) {
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "%"))
+	if s == "" {
+		return 0, nil
+	}
+
+	invalid := fmt.Errorf("invalid tax rate: %s (expected a percentage between 0 and 100)", s)
+	value, scale, err := parseDecimal(s, localeFormat(locale))
+	if err != nil {
+		return 0, invalid
+	}
+	if scale > 4 {
+		return 0, fmt.Errorf("invalid tax rate: %s (at most 4 decimal places)", s)
+	}
+	if value > 100*pow10(scale) {
+		return 0, invalid
+	}
+	return api.Percent(value * pow10(4-scale)), nil
+}
+
+// lineTax returns the tax on a line item: subtotal × rate, rounded half up
+func lineTax(item api.LineItem) (int64, error) {
+	subtotal, rate := item.Subtotal(), int64(item.TaxRate)
+	if rate == 0 {
+		return 0, nil
+	}
+	if subtotal > math.MaxInt64/rate {
+		return 0, fmt.Errorf("line total is too large")
+	}
+	unit := int64(100 * api.PercentScale)
+	tax, rem := subtotal*rate/unit, subtotal*rate%unit
+	if rem*2 >= unit {
+		tax++
+	}
+	return tax, nil
+}
+
+// itemTotals returns the subtotal and tax across all line items, failing
+// if the invoice total doesn't fit in an int64
+func itemTotals(items []api.LineItem) (subtotal, tax int64, err error) {
+	for _, item := range items {
+		lt, err := lineTax(item)
+		if err != nil {
+			return 0, 0, err
+		}
+		if item.Subtotal() > math.MaxInt64-subtotal || lt > math.MaxInt64-tax {
+			return 0, 0, fmt.Errorf("invoice total is too large")
+		}
+		subtotal += item.Subtotal()
+		tax += lt
+	}
+	if tax > math.MaxInt64-subtotal {
+		return 0, 0, fmt.Errorf("invoice total is too large")
+	}
+	return subtotal, tax, nil
+}
+
+// formatItems renders line items we are about to send as a table followed
+// by subtotal, tax and total
+func formatItems(items []api.LineItem, cur currency.Currency) string {
+	amounts := make([]int64, len(items))
+	for i, item := range items {
+		amounts[i] = item.Subtotal()
+	}
+	subtotal, tax, _ := itemTotals(items) // Checked when the request was built
+	return itemsTable(items, amounts, subtotal, tax, subtotal+tax, cur)
+}
+
+// formatInvoiceItems renders an invoice's line items with the amounts the
+// API calculated, so they match what the client is billed
+func formatInvoiceItems(inv *api.Invoice, cur currency.Currency) string {
+	amounts := make([]int64, len(inv.LineItems))
+	for i, item := range inv.LineItems {
+		amounts[i] = item.Amount
+	}
+	return itemsTable(inv.LineItems, amounts, inv.Subtotal, inv.Tax, inv.Total, cur)
+}
+
+// itemsTable renders line items with their amounts, then the totals
+func itemsTable(items []api.LineItem, amounts []int64, subtotal, tax, total int64, cur currency.Currency) string {
+	rows := make([][]string, 0, len(items))
+	for i, item := range items {
+		taxRate := ""
+		if item.TaxRate > 0 {
+			taxRate = item.TaxRate.String() + "%"
+		}
+		rows = append(rows, []string{
+			item.Description,
+			strconv.FormatInt(item.Quantity, 10),
+			cur.Format(item.UnitAmount),
+			taxRate,
+			cur.Format(amounts[i]),
+		})
+	}
+
+	var b strings.Builder
+	b.WriteString(ui.Table([]string{"Item", "Qty", "Unit Price", "Tax", "Amount"}, rows, 1, 2, 3, 4))
+	b.WriteString("\n\n")
+	b.WriteString(ui.FormatLabel("Subtotal", cur.Format(subtotal)))
+	b.WriteString("\n")
+	b.WriteString(ui.FormatLabel("Tax", cur.Format(tax)))
+	b.WriteString("\n")
+	b.WriteString(ui.Label.Render("Total: "))
+	b.WriteString(ui.FormatAmount(total, cur))
+	return b.String()
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"math"
+	"testing"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/forrestcai35/lane/internal/currency"
+)
+
+func TestParseItem(t *testing.T) {
+	usd, _ := currency.Lookup("usd")
+
+	tests := []struct {
+		name     string
+		spec     string
+		expected api.LineItem
+		wantErr  bool
+	}{
+		{
+			name:     "quantity and price",
+			spec:     "Design:3x150",
+			expected: api.LineItem{Description: "Design", Quantity: 3, UnitAmount: 15000},
+		},
+		{
+			name:     "price only",
+			spec:     "Hosting:20",
+			expected: api.LineItem{Description: "Hosting", Quantity: 1, UnitAmount: 2000},
+		},
+		{
+			name:     "with tax",
+			spec:     "Consulting:10x95.50@20%",
+			expected: api.LineItem{Description: "Consulting", Quantity: 10, UnitAmount: 9550, TaxRate: 20 * api.PercentScale},
+		},
+		{
+			name:     "with fractional tax",
+			spec:     "Consulting:100@1.005%",
+			expected: api.LineItem{Description: "Consulting", Quantity: 1, UnitAmount: 10000, TaxRate: 10050},
+		},
+		{
+			name:     "description with colon",
+			spec:     "Phase 2: build:1x1,000",
+			expected: api.LineItem{Description: "Phase 2: build", Quantity: 1, UnitAmount: 100000},
+		},
+		{name: "missing price", spec: "Design", wantErr: true},
+		{name: "missing description", spec: ":3x150", wantErr: true},
+		{name: "zero quantity", spec: "Design:0x150", wantErr: true},
+		{name: "fractional quantity", spec: "Design:1.5x150", wantErr: true},
+		{name: "bad price", spec: "Design:3xabc", wantErr: true},
+		{name: "bad tax", spec: "Design:3x150@abc", wantErr: true},
+		{name: "tax over 100", spec: "Design:3x150@120%", wantErr: true},
+		{name: "tax with too many decimals", spec: "Design:3x150@8.12345%", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseItem(tt.spec, usd, "en")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseItem(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.expected {
+				t.Errorf("parseItem(%q) = %+v, want %+v", tt.spec, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestItemTotals(t *testing.T) {
+	items := []api.LineItem{
+		{Description: "Design", Quantity: 3, UnitAmount: 15000, TaxRate: 20 * api.PercentScale},
+		{Description: "Hosting", Quantity: 1, UnitAmount: 1999, TaxRate: 85000},
+		{Description: "Support", Quantity: 2, UnitAmount: 500},
+		{Description: "Audit", Quantity: 1, UnitAmount: 10000, TaxRate: 10050},
+	}
+
+	subtotal, tax, err := itemTotals(items)
+	if err != nil {
+		t.Fatalf("itemTotals() error = %v", err)
+	}
+	if subtotal != 57999 {
+		t.Errorf("subtotal = %d, want 57999", subtotal)
+	}
+	// 9000 + 169.915 (up to 170) + 0 + 100.5 (half up to 101)
+	if tax != 9271 {
+		t.Errorf("tax = %d, want 9271", tax)
+	}
+
+	huge := []api.LineItem{
+		{Description: "A", Quantity: 1, UnitAmount: math.MaxInt64 / 2},
+		{Description: "B", Quantity: 1, UnitAmount: math.MaxInt64 / 2},
+		{Description: "C", Quantity: 1, UnitAmount: 2},
+	}
+	if _, _, err := itemTotals(huge); err == nil {
+		t.Error("itemTotals() expected error when the total overflows")
+	}
+	taxed := []api.LineItem{{Description: "A", Quantity: 1, UnitAmount: math.MaxInt64 / 2, TaxRate: 20 * api.PercentScale}}
+	if _, _, err := itemTotals(taxed); err == nil {
+		t.Error("itemTotals() expected error when the tax overflows")
+	}
+}
+
+func TestParseManifest(t *testing.T) {
+	setTempHome(t)
+
+	manifest := `
+client: Acme Corp
+email: ap@acme.com
+currency: eur
+description: March retainer
+send: true
+items:
+  - description: Design
+    quantity: 3
+    unit_price: 150
+    tax_rate: 20
+  - description: Hosting
+    unit_price: "19.99"
+`
+	req, cur, err := parseManifest([]byte(manifest))
+	if err != nil {
+		t.Fatalf("parseManifest() error = %v", err)
+	}
+
+	if cur.Code != "EUR" || req.Currency != "eur" {
+		t.Errorf("currency = %s/%s, want EUR/eur", cur.Code, req.Currency)
+	}
+	if req.ClientName != "Acme Corp" || req.ClientEmail != "ap@acme.com" || !req.SendEmail {
+		t.Errorf("unexpected client fields: %+v", req)
+	}
+	if len(req.LineItems) != 2 {
+		t.Fatalf("got %d line items, want 2", len(req.LineItems))
+	}
+	if req.LineItems[1].Quantity != 1 || req.LineItems[1].UnitAmount != 1999 {
+		t.Errorf("unexpected second item: %+v", req.LineItems[1])
+	}
+	// 3 x 150.00 + 20% tax + 19.99
+	if req.Amount != 54000+1999 {
+		t.Errorf("Amount = %d, want %d", req.Amount, 54000+1999)
+	}
+}
+
+func TestParseManifestLocale(t *testing.T) {
+	setTempHome(t)
+	if err := config.SetUserSetting("locale", "de"); err != nil {
+		t.Fatalf("SetUserSetting() error = %v", err)
+	}
+
+	manifest := "currency: eur\nitems:\n  - description: A\n    unit_price: \"1.234,50\"\n"
+	req, _, err := parseManifest([]byte(manifest))
+	if err != nil {
+		t.Fatalf("parseManifest() error = %v", err)
+	}
+	if req.LineItems[0].UnitAmount != 123450 {
+		t.Errorf("UnitAmount = %d, want 123450 with the de locale setting", req.LineItems[0].UnitAmount)
+	}
+
+	// The manifest's own locale wins
+	manifest = "currency: eur\nlocale: en\nitems:\n  - description: A\n    unit_price: \"1,234.50\"\n"
+	if req, _, err = parseManifest([]byte(manifest)); err != nil {
+		t.Fatalf("parseManifest() error = %v", err)
+	}
+	if req.LineItems[0].UnitAmount != 123450 {
+		t.Errorf("UnitAmount = %d, want 123450 with locale en", req.LineItems[0].UnitAmount)
+	}
+}
+
+func TestCreateFlagsAreLocal(t *testing.T) {
+	defer func() {
+		createCmd.Flags().Set("draft", "false")
+		createCmd.Flags().Set("no-copy", "false")
+	}()
+
+	if err := createCmd.Flags().Set("draft", "true"); err != nil {
+		t.Fatalf("Set(draft) error = %v", err)
+	}
+	if err := createCmd.Flags().Set("no-copy", "true"); err != nil {
+		t.Fatalf("Set(no-copy) error = %v", err)
+	}
+	if draft || noCopy {
+		t.Error("create's --draft and --no-copy changed the root command's flags")
+	}
+	if !createDraft || !createNoCopy {
+		t.Error("create's --draft and --no-copy weren't set")
+	}
+}
+
+func TestParseManifestErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+	}{
+		{"no items", "client: Acme\n"},
+		{"unknown currency", "currency: xyz\nitems:\n  - description: A\n    unit_price: 1\n"},
+		{"send without email", "send: true\nitems:\n  - description: A\n    unit_price: 1\n"},
+		{"send and draft", "send: true\ndraft: true\nemail: a@b.co\nitems:\n  - description: A\n    unit_price: 1\n"},
+		{"bad unit price", "items:\n  - description: A\n    unit_price: abc\n"},
+		{"zero quantity", "items:\n  - description: A\n    quantity: 0\n    unit_price: 1\n"},
+		{"invalid yaml", "items: [\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := parseManifest([]byte(tt.manifest)); err == nil {
+				t.Errorf("parseManifest() expected error")
+			}
+		})
+	}
+}
@@ -1,14 +1,19 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/exec"
 	"runtime"
+	"strings"
 	"time"
 
+	"github.com/forrestcai35/lane/internal/api"
 	"github.com/forrestcai35/lane/internal/config"
 	"github.com/forrestcai35/lane/internal/ui"
 	"github.com/spf13/cobra"
@@ -20,19 +25,87 @@ var loginCmd = &cobra.Command{
 	Long: `Log in to your Lane account.
 
 This will open your browser to authenticate. Once complete,
-your CLI will be automatically connected.`,
+your CLI will be automatically connected.
+
+With --loopback, the browser hands the login back to a temporary
+listener on 127.0.0.1, secured with PKCE, instead of Lane polling for
+it.
+
+With --no-browser, Lane prints a short code, a URL and a QR code
+instead, so you can approve the login from your phone or another
+computer. Use it over SSH or inside containers.
+
+With --with-token, a token is read from standard input, checked with
+the API and saved, replacing any existing login. Use it to set up CI
+from a secret store.
+
+With --profile, the login is saved to that profile only, creating it
+if needed.`,
+	Example: `  lane login
+  lane login --no-browser
+  lane login --loopback
+  lane login --with-token < token.txt
+  lane login --profile work`,
 	RunE: runLogin,
 }
 
+var (
+	// Login flags
+	noBrowser bool
+	loopback  bool
+	withToken bool
+)
+
 func init() {
+	loginCmd.Flags().BoolVar(&noBrowser, "no-browser", false, "Show a code and QR code to approve on another device instead of opening a browser")
+	loginCmd.Flags().BoolVar(&loopback, "loopback", false, "Receive the login on a local redirect (PKCE) instead of polling")
+	loginCmd.Flags().BoolVar(&withToken, "with-token", false, "Read a token from standard input instead of logging in through the browser")
+	loginCmd.MarkFlagsMutuallyExclusive("no-browser", "loopback", "with-token")
 	rootCmd.AddCommand(loginCmd)
 }
 
 type cliAuthResponse struct {
-	Code   string `json:"code,omitempty"`
-	Token  string `json:"token,omitempty"`
-	Status string `json:"status,omitempty"`
-	Error  string `json:"error,omitempty"`
+	Code            string `json:"code,omitempty"`
+	Token           string `json:"token,omitempty"`
+	Status          string `json:"status,omitempty"` // "pending" or "slow_down" while waiting
+	Error           string `json:"error,omitempty"`
+	UserCode        string `json:"user_code,omitempty"`        // Short code shown on the approval page
+	VerificationURL string `json:"verification_url,omitempty"` // Page where the user code is entered
+	Interval        int    `json:"interval,omitempty"`         // Seconds to wait between polls
+	ExpiresIn       int    `json:"expires_in,omitempty"`       // Seconds until the code, or once issued the token, expires
+	RefreshToken    string `json:"refresh_token,omitempty"`    // Renews the token when it expires
+}
+
+// credentials converts a response carrying a token into credentials to store
+func (r cliAuthResponse) credentials() config.Credentials {
+	token := api.TokenResponse{Token: r.Token, RefreshToken: r.RefreshToken, ExpiresIn: r.ExpiresIn}
+	return token.Credentials(time.Now())
+}
+
+const (
+	// Polling defaults when the server doesn't send interval/expires_in
+	defaultPollInterval = 2 * time.Second
+	defaultLoginTimeout = 5 * time.Minute
+
+	// slowDownStep is added to the interval on each slow_down response
+	slowDownStep = 5 * time.Second
+)
+
+// sleep waits between polls (replaced in tests)
+var sleep = sleepContext
+
+// sleepContext waits for d, returning early with the context's error if
+// it is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func runLogin(cmd *cobra.Command, args []string) error {
@@ -40,34 +113,117 @@ func runLogin(cmd *cobra.Command, args []string) error {
 	fmt.Println(ui.Logo.Render("⚡ Lane Login"))
 	fmt.Println()
 
-	// Check if already logged in
-	if config.IsLoggedIn() {
+	ctx := cmd.Context()
+	if withToken {
+		return runTokenLogin(ctx)
+	}
+
+	// Check if this profile already has a login. LANE_TOKEN doesn't count,
+	// since logging in is how to stop depending on it.
+	if config.HasStoredCredentials() {
 		fmt.Println(ui.Subtle.Render("Already logged in. Use 'lane logout' to switch accounts."))
 		return nil
 	}
 
-	apiURL := config.GetAPIURL()
+	profile := config.GetProfile()
+	if profile != config.DefaultProfile {
+		fmt.Println(ui.Subtle.Render("Profile: " + profile))
+	}
 
-	// Step 1: Create pending auth session
+	apiURL := config.GetAPIURL()
 	fmt.Println(ui.FormatStep("Starting authentication..."))
 	fmt.Println(ui.Subtle.Render("API: " + apiURL))
 
-	resp, err := http.Post(apiURL+"/api/auth/cli", "application/json", nil)
+	var creds config.Credentials
+	var err error
+	if loopback {
+		creds, err = runLoopbackLogin(ctx, apiURL)
+	} else {
+		creds, err = runPollingLogin(ctx, apiURL)
+	}
+	if cancelled(err) {
+		reportCancelled(cmd, "Login cancelled. Nothing was saved.")
+		return err
+	}
 	if err != nil {
-		fmt.Println(ui.FormatError("Failed to connect to Lane API: " + err.Error()))
+		if !isNetworkError(err) {
+			err = withExitCode(exitNotAuthenticated, err)
+		}
 		return err
 	}
+
+	return saveLogin(creds, profile)
+}
+
+// runTokenLogin saves a token read from stdin once the API accepts it
+func runTokenLogin(ctx context.Context) error {
+	token, err := readToken(stdin)
+	if err != nil {
+		fmt.Println(formatError(err))
+		return usageError(err)
+	}
+
+	fmt.Println(ui.FormatStep("Verifying token..."))
+	user, err := api.NewClientWithToken(token).GetCurrentUserContext(ctx)
+	if errors.Is(err, api.ErrUnauthorized) {
+		err = withExitCode(exitNotAuthenticated, fmt.Errorf("the token was rejected: it is invalid, expired or revoked"))
+	}
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+	fmt.Println(ui.Subtle.Render("Token belongs to " + user.Email))
+
+	return saveLogin(config.Credentials{Token: token}, config.GetProfile())
+}
+
+// readToken reads a token from r, ignoring surrounding whitespace
+func readToken(r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, 64*1024))
+	if err != nil {
+		return "", fmt.Errorf("could not read token: %w", err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("no token on standard input. Pipe one in, e.g. lane login --with-token < token.txt")
+	}
+	if strings.ContainsAny(token, " \t\r\n") {
+		return "", fmt.Errorf("standard input should contain only the token")
+	}
+	return token, nil
+}
+
+// runPollingLogin creates a pending login, sends the user to approve it
+// and polls until it is approved
+func runPollingLogin(ctx context.Context, apiURL string) (config.Credentials, error) {
+	// Step 1: Create pending auth session
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL+"/api/auth/cli", nil)
+	if err != nil {
+		fmt.Println(formatError(err))
+		return config.Credentials{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return config.Credentials{}, ctx.Err()
+		}
+		fmt.Println(ui.FormatError("Failed to connect to Lane API: " + err.Error()))
+		return config.Credentials{}, err
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		fmt.Println(ui.FormatError(fmt.Sprintf("API returned status %d", resp.StatusCode)))
-		return fmt.Errorf("API error: %s", resp.Status)
+		return config.Credentials{}, fmt.Errorf("API error: %s", resp.Status)
 	}
 
 	var authResp cliAuthResponse
 	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
 		fmt.Println(ui.FormatError("Failed to parse response: " + err.Error()))
-		return err
+		return config.Credentials{}, err
 	}
 
 	if authResp.Code == "" {
@@ -76,37 +232,91 @@ func runLogin(cmd *cobra.Command, args []string) error {
 		} else {
 			fmt.Println(ui.FormatError("Failed to start authentication - no code received"))
 		}
-		return fmt.Errorf("no auth code received")
+		return config.Credentials{}, fmt.Errorf("no auth code received")
 	}
 
-	// Step 2: Open browser
+	// Step 2: Open browser, or show the code to approve elsewhere
 	authURL := fmt.Sprintf("%s/auth/cli?code=%s", apiURL, authResp.Code)
-	fmt.Println(ui.FormatStep("Opening browser..."))
-	fmt.Println(ui.Subtle.Render(authURL))
-	fmt.Println()
-
-	if err := openBrowser(authURL); err != nil {
-		fmt.Println(ui.Subtle.Render("Could not open browser. Please visit the URL above."))
+	if noBrowser {
+		printDeviceCode(authResp, authURL)
+	} else {
+		fmt.Println(ui.FormatStep("Opening browser..."))
+		fmt.Println(ui.Subtle.Render(authURL))
+		fmt.Println()
+
+		if err := openBrowser(authURL); err != nil {
+			fmt.Println(ui.Subtle.Render("Could not open browser. Please visit the URL above."))
+		}
 	}
 
 	// Step 3: Poll for token
 	fmt.Println(ui.FormatStep("Waiting for authentication..."))
-	fmt.Println(ui.Subtle.Render("Complete the login in your browser."))
+	if noBrowser {
+		fmt.Println(ui.Subtle.Render("Approve the login on your other device."))
+	} else {
+		fmt.Println(ui.Subtle.Render("Complete the login in your browser."))
+	}
 	fmt.Println()
 
-	token, err := pollForToken(apiURL, authResp.Code)
+	creds, err := pollForToken(ctx, apiURL, authResp)
 	if err != nil {
-		fmt.Println(ui.FormatError(err.Error()))
-		return err
+		if !cancelled(err) {
+			fmt.Println(formatError(err))
+		}
+		return config.Credentials{}, err
 	}
+	return creds, nil
+}
+
+// runLoopbackLogin opens the browser and waits for it to redirect back to
+// a local listener
+func runLoopbackLogin(ctx context.Context, apiURL string) (config.Credentials, error) {
+	open := func(authURL string) error {
+		fmt.Println(ui.FormatStep("Opening browser..."))
+		fmt.Println(ui.Subtle.Render(authURL))
+		fmt.Println()
+		if err := openBrowser(authURL); err != nil {
+			fmt.Println(ui.Subtle.Render("Could not open browser. Please visit the URL above."))
+		}
 
-	// Step 4: Save token
-	if err := config.SaveAuthToken(token); err != nil {
-		fmt.Println(ui.FormatError("Failed to save token"))
+		fmt.Println(ui.FormatStep("Waiting for authentication..."))
+		fmt.Println(ui.Subtle.Render("Complete the login in your browser."))
+		fmt.Println()
+		return nil
+	}
+
+	creds, err := loopbackLogin(ctx, apiURL, open, defaultLoginTimeout)
+	if err != nil {
+		if !cancelled(err) {
+			fmt.Println(formatError(err))
+		}
+		return config.Credentials{}, err
+	}
+	return creds, nil
+}
+
+// saveLogin stores a new login for a profile
+func saveLogin(creds config.Credentials, profile string) error {
+	store, err := config.GetCredentialStore()
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+	if err := config.SaveCredentials(creds); err != nil {
+		fmt.Println(ui.FormatError("Failed to save token: " + err.Error()))
 		return err
 	}
 
 	fmt.Println(ui.FormatSuccess("✓ Logged in successfully!"))
+	fmt.Println(ui.Subtle.Render("Token saved to " + describeStore(store)))
+
+	// Remember new profiles so they show up in 'lane profiles list'
+	if exists, _ := config.ProfileExists(profile); !exists {
+		if err := config.SaveProfile(config.Profile{Name: profile}); err != nil {
+			fmt.Println(ui.FormatError("Failed to save profile: " + err.Error()))
+			return err
+		}
+	}
 	fmt.Println()
 	fmt.Println(ui.Subtle.Render("You can now create invoices with:"))
 	fmt.Println(ui.Label.Render("  lane 100 --client \"Acme\" --desc \"Consulting\""))
@@ -115,44 +325,100 @@ func runLogin(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func pollForToken(apiURL, code string) (string, error) {
+// printDeviceCode shows how to approve the login from another device
+func printDeviceCode(authResp cliAuthResponse, authURL string) {
+	fmt.Println(ui.FormatStep("To log in, scan the QR code or open this URL on any device:"))
+	fmt.Println()
+
+	link := deviceCodeLink(authResp, authURL)
+	if qr, err := ui.QRCode(link); err == nil {
+		fmt.Println(qr)
+		fmt.Println()
+	}
+
+	fmt.Println("  " + ui.FormatLink(link))
+	if link == authResp.VerificationURL {
+		fmt.Println("  " + ui.FormatLabel("Code", authResp.UserCode))
+	}
+	fmt.Println()
+}
+
+// deviceCodeLink returns the URL to show and encode in the QR code. When
+// the server issues a user code, that is the verification page, so the
+// device code in authURL never leaves this terminal.
+func deviceCodeLink(authResp cliAuthResponse, authURL string) string {
+	if authResp.VerificationURL != "" && authResp.UserCode != "" {
+		return authResp.VerificationURL
+	}
+	return authURL
+}
+
+// pollForToken polls until the login is approved, waiting the interval the
+// server asks for and backing off further whenever it answers slow_down.
+// It stops with the context's error when ctx is cancelled.
+func pollForToken(ctx context.Context, apiURL string, authResp cliAuthResponse) (config.Credentials, error) {
 	client := &http.Client{Timeout: 10 * time.Second}
-	pollURL := fmt.Sprintf("%s/api/auth/cli?code=%s", apiURL, code)
+	pollURL := fmt.Sprintf("%s/api/auth/cli?code=%s", apiURL, authResp.Code)
 
-	// Poll for up to 5 minutes
-	deadline := time.Now().Add(5 * time.Minute)
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
+	interval := defaultPollInterval
+	if authResp.Interval > 0 {
+		interval = time.Duration(authResp.Interval) * time.Second
+	}
+	timeout := defaultLoginTimeout
+	if authResp.ExpiresIn > 0 {
+		timeout = time.Duration(authResp.ExpiresIn) * time.Second
+	}
+	deadline := time.Now().Add(timeout)
 
 	for {
-		select {
-		case <-ticker.C:
-			if time.Now().After(deadline) {
-				return "", fmt.Errorf("authentication timed out")
-			}
+		if err := sleep(ctx, interval); err != nil {
+			return config.Credentials{}, err
+		}
+		if time.Now().After(deadline) {
+			return config.Credentials{}, fmt.Errorf("authentication timed out")
+		}
 
-			resp, err := client.Get(pollURL)
-			if err != nil {
-				continue // Retry on network error
+		req, err := http.NewRequestWithContext(ctx, "GET", pollURL, nil)
+		if err != nil {
+			return config.Credentials{}, fmt.Errorf("failed to create request: %w", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return config.Credentials{}, ctx.Err()
 			}
+			continue // Retry on network error
+		}
 
-			var authResp cliAuthResponse
-			if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
-				resp.Body.Close()
-				continue
-			}
-			resp.Body.Close()
+		var pollResp cliAuthResponse
+		err = json.NewDecoder(resp.Body).Decode(&pollResp)
+		resp.Body.Close()
+		if err != nil {
+			continue
+		}
 
-			if authResp.Token != "" {
-				return authResp.Token, nil
-			}
+		if pollResp.Token != "" {
+			return pollResp.credentials(), nil
+		}
 
-			if authResp.Error != "" {
-				return "", fmt.Errorf(authResp.Error)
-			}
+		if pollResp.Interval > 0 {
+			interval = time.Duration(pollResp.Interval) * time.Second
+		}
 
-			// Status is "pending", keep polling
+		switch {
+		case pollResp.Status == "slow_down" || pollResp.Error == "slow_down":
+			interval += slowDownStep
+		case pollResp.Error == "authorization_pending":
+			// Not approved yet
+		case pollResp.Error == "expired_token":
+			return config.Credentials{}, fmt.Errorf("the login code expired. Run 'lane login' again")
+		case pollResp.Error == "access_denied":
+			return config.Credentials{}, fmt.Errorf("the login was denied")
+		case pollResp.Error != "":
+			return config.Credentials{}, fmt.Errorf("%s", pollResp.Error)
 		}
+
+		// Status is "pending", keep polling
 	}
 }
 
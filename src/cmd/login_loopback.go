@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/forrestcai35/lane/internal/config"
+)
+
+// loopbackPage is shown in the browser once the redirect arrives
+const loopbackPage = `<!doctype html>
+<html><head><meta charset="utf-8"><title>Lane</title></head>
+<body style="font-family: sans-serif; text-align: center; margin-top: 4em">
+<h1>%s</h1><p>%s</p>
+</body></html>`
+
+// pkce holds the secrets for one loopback login
+type pkce struct {
+	verifier  string // Kept locally, sent only when exchanging the code
+	challenge string // SHA-256 of the verifier, sent with the browser request
+	state     string // Ties the redirect to this login attempt
+}
+
+// newPKCE generates a fresh code verifier, challenge and state (RFC 7636)
+func newPKCE() (pkce, error) {
+	verifier, err := randomString(32)
+	if err != nil {
+		return pkce{}, err
+	}
+	state, err := randomString(16)
+	if err != nil {
+		return pkce{}, err
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	return pkce{
+		verifier:  verifier,
+		challenge: base64.RawURLEncoding.EncodeToString(sum[:]),
+		state:     state,
+	}, nil
+}
+
+// randomString returns n random bytes, base64url-encoded
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("could not generate random data: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// callbackResult is what the loopback listener received
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// loopbackLogin authenticates through the browser, receiving the
+// authorization code on a short-lived 127.0.0.1 listener and exchanging it
+// for a token with the PKCE verifier. open is called with the URL to visit.
+func loopbackLogin(ctx context.Context, apiURL string, open func(string) error, timeout time.Duration) (config.Credentials, error) {
+	p, err := newPKCE()
+	if err != nil {
+		return config.Credentials{}, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return config.Credentials{}, fmt.Errorf("could not start local listener: %w", err)
+	}
+	redirectURI := fmt.Sprintf("http://%s/callback", listener.Addr())
+
+	results := make(chan callbackResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		// Anything on the port can request /callback; only the redirect
+		// carrying this login's state may finish it
+		if r.URL.Query().Get("state") != p.state {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, loopbackPage, "Login failed", "This link doesn't belong to the current login.")
+			return
+		}
+
+		result := readCallback(r.URL.Query())
+		if result.err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, loopbackPage, "Login failed", html.EscapeString(result.err.Error()))
+		} else {
+			fmt.Fprintf(w, loopbackPage, "Logged in to Lane", "You can close this tab and return to your terminal.")
+		}
+
+		select {
+		case results <- result:
+		default: // Only the first redirect counts
+		}
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+
+	params := url.Values{
+		"redirect_uri":          {redirectURI},
+		"code_challenge":        {p.challenge},
+		"code_challenge_method": {"S256"},
+		"state":                 {p.state},
+	}
+	if err := open(apiURL + "/auth/cli?" + params.Encode()); err != nil {
+		return config.Credentials{}, err
+	}
+
+	select {
+	case result := <-results:
+		if result.err != nil {
+			return config.Credentials{}, result.err
+		}
+		return exchangeCode(ctx, apiURL, result.code, p.verifier, redirectURI)
+	case <-time.After(timeout):
+		return config.Credentials{}, fmt.Errorf("authentication timed out")
+	case <-ctx.Done():
+		return config.Credentials{}, ctx.Err()
+	}
+}
+
+// readCallback reads the result from a redirect whose state has been
+// checked
+func readCallback(query url.Values) callbackResult {
+	if msg := query.Get("error"); msg != "" {
+		if msg == "access_denied" {
+			return callbackResult{err: errors.New("the login was denied")}
+		}
+		return callbackResult{err: fmt.Errorf("login failed: %s", msg)}
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		return callbackResult{err: errors.New("login failed: no authorization code received")}
+	}
+	return callbackResult{code: code}
+}
+
+// exchangeCode trades an authorization code and PKCE verifier for a token
+func exchangeCode(ctx context.Context, apiURL, code, verifier, redirectURI string) (config.Credentials, error) {
+	body, err := json.Marshal(map[string]string{
+		"grant_type":    "authorization_code",
+		"code":          code,
+		"code_verifier": verifier,
+		"redirect_uri":  redirectURI,
+	})
+	if err != nil {
+		return config.Credentials{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL+"/api/auth/cli/token", bytes.NewReader(body))
+	if err != nil {
+		return config.Credentials{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return config.Credentials{}, ctx.Err()
+		}
+		return config.Credentials{}, fmt.Errorf("failed to connect to Lane API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var authResp cliAuthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
+		return config.Credentials{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || authResp.Token == "" {
+		if authResp.Error != "" {
+			return config.Credentials{}, fmt.Errorf("login failed: %s", authResp.Error)
+		}
+		return config.Credentials{}, fmt.Errorf("login failed: API error: %s", resp.Status)
+	}
+	return authResp.credentials(), nil
+}
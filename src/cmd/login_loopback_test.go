@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeAuthServer plays both the browser approval page and the token
+// endpoint. The approval page redirects back with the given query, or with
+// a code when redirect is nil. The request's state is passed back unless
+// the query sets its own.
+func fakeAuthServer(t *testing.T, redirect url.Values) *httptest.Server {
+	t.Helper()
+
+	var challenge, redirectURI string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/cli", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" || q.Get("state") == "" {
+			t.Errorf("missing PKCE parameters: %s", r.URL.RawQuery)
+		}
+		redirectURI = q.Get("redirect_uri")
+		if !strings.HasPrefix(redirectURI, "http://127.0.0.1:") {
+			t.Errorf("redirect_uri = %q, want a loopback address", redirectURI)
+		}
+		challenge = q.Get("code_challenge")
+
+		back := url.Values{"code": {"auth_code"}}
+		if redirect != nil {
+			back = url.Values{}
+			for k, v := range redirect {
+				back[k] = v
+			}
+		}
+		if !back.Has("state") {
+			back.Set("state", q.Get("state"))
+		}
+		http.Redirect(w, r, redirectURI+"?"+back.Encode(), http.StatusFound)
+	})
+	mux.HandleFunc("/api/auth/cli/token", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+
+		sum := sha256.Sum256([]byte(body["code_verifier"]))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(cliAuthResponse{Error: "invalid code_verifier"})
+			return
+		}
+		if body["code"] != "auth_code" || body["redirect_uri"] != redirectURI {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(cliAuthResponse{Error: "invalid grant"})
+			return
+		}
+		json.NewEncoder(w).Encode(cliAuthResponse{Token: "lane_token"})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// fakeBrowser follows the auth URL and its redirect back to the listener
+func fakeBrowser(t *testing.T) func(string) error {
+	return func(authURL string) error {
+		go func() {
+			resp, err := http.Get(authURL)
+			if err != nil {
+				t.Errorf("browser request failed: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+		return nil
+	}
+}
+
+func TestLoopbackLogin(t *testing.T) {
+	server := fakeAuthServer(t, nil)
+
+	creds, err := loopbackLogin(context.Background(), server.URL, fakeBrowser(t), 5*time.Second)
+	if err != nil {
+		t.Fatalf("loopbackLogin() error = %v", err)
+	}
+	if creds.Token != "lane_token" {
+		t.Errorf("loopbackLogin() = %q, want lane_token", creds.Token)
+	}
+}
+
+func TestLoopbackLoginErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		redirect url.Values
+		want     string
+	}{
+		{"denied", url.Values{"error": {"access_denied"}}, "denied"},
+		{"no code", url.Values{}, "no authorization code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := fakeAuthServer(t, tt.redirect)
+
+			_, err := loopbackLogin(context.Background(), server.URL, fakeBrowser(t), 5*time.Second)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("loopbackLogin() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoopbackLoginIgnoresForgedCallback(t *testing.T) {
+	server := fakeAuthServer(t, nil)
+	browser := fakeBrowser(t)
+
+	open := func(authURL string) error {
+		u, _ := url.Parse(authURL)
+		callback := u.Query().Get("redirect_uri")
+
+		forged := url.Values{"state": {"forged"}, "error": {"<script>alert(1)</script>"}}
+		resp, err := http.Get(callback + "?" + forged.Encode())
+		if err != nil {
+			return err
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("forged callback status = %d, want 400", resp.StatusCode)
+		}
+		if strings.Contains(string(body), "<script>") {
+			t.Errorf("forged callback page echoes the error unescaped:\n%s", body)
+		}
+
+		return browser(authURL)
+	}
+
+	creds, err := loopbackLogin(context.Background(), server.URL, open, 5*time.Second)
+	if err != nil {
+		t.Fatalf("loopbackLogin() error = %v, want the real redirect to finish the login", err)
+	}
+	if creds.Token != "lane_token" {
+		t.Errorf("loopbackLogin() = %q, want lane_token", creds.Token)
+	}
+}
+
+func TestLoopbackLoginEscapesError(t *testing.T) {
+	server := fakeAuthServer(t, url.Values{"error": {"<script>alert(1)</script>"}})
+
+	pages := make(chan string, 1)
+	open := func(authURL string) error {
+		go func() {
+			resp, err := http.Get(authURL)
+			if err != nil {
+				t.Errorf("browser request failed: %v", err)
+				return
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			pages <- string(body)
+		}()
+		return nil
+	}
+
+	if _, err := loopbackLogin(context.Background(), server.URL, open, 5*time.Second); err == nil {
+		t.Fatal("loopbackLogin() error = nil, want the login to fail")
+	}
+	page := <-pages
+	if strings.Contains(page, "<script>") || !strings.Contains(page, "&lt;script&gt;") {
+		t.Errorf("error page does not escape the error:\n%s", page)
+	}
+}
+
+func TestLoopbackLoginTimeout(t *testing.T) {
+	server := fakeAuthServer(t, nil)
+
+	_, err := loopbackLogin(context.Background(), server.URL, func(string) error { return nil }, 50*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("loopbackLogin() error = %v, want a timeout", err)
+	}
+}
+
+func TestLoopbackLoginCancelled(t *testing.T) {
+	server := fakeAuthServer(t, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	open := func(string) error { cancel(); return nil }
+
+	if _, err := loopbackLogin(ctx, server.URL, open, time.Minute); !cancelled(err) {
+		t.Errorf("loopbackLogin() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestNewPKCE(t *testing.T) {
+	a, err := newPKCE()
+	if err != nil {
+		t.Fatalf("newPKCE() error = %v", err)
+	}
+	b, _ := newPKCE()
+
+	if a.verifier == b.verifier || a.state == b.state {
+		t.Error("newPKCE() returned the same secrets twice")
+	}
+	if len(a.verifier) < 43 {
+		t.Errorf("verifier length = %d, want at least 43 (RFC 7636)", len(a.verifier))
+	}
+
+	sum := sha256.Sum256([]byte(a.verifier))
+	if a.challenge != base64.RawURLEncoding.EncodeToString(sum[:]) {
+		t.Error("challenge is not the S256 hash of the verifier")
+	}
+}
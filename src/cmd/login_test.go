@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/forrestcai35/lane/internal/testutil"
+)
+
+// fakePoller serves the given poll responses in order
+func fakePoller(t *testing.T, responses ...cliAuthResponse) *httptest.Server {
+	t.Helper()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/auth/cli" || r.URL.Query().Get("code") != "abc123" {
+			t.Errorf("unexpected poll %s", r.URL)
+		}
+		if calls >= len(responses) {
+			t.Fatalf("polled %d times, want at most %d", calls+1, len(responses))
+		}
+		json.NewEncoder(w).Encode(responses[calls])
+		calls++
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPollForToken(t *testing.T) {
+	waits := testutil.RecordSleeps(t, &sleep)
+	server := fakePoller(t,
+		cliAuthResponse{Status: "pending"},
+		cliAuthResponse{Status: "slow_down"},
+		cliAuthResponse{Error: "authorization_pending"},
+		cliAuthResponse{Error: "slow_down", Interval: 10},
+		cliAuthResponse{Token: "lane_token", RefreshToken: "lane_refresh", ExpiresIn: 3600},
+	)
+
+	creds, err := pollForToken(context.Background(), server.URL, cliAuthResponse{Code: "abc123", Interval: 3})
+	if err != nil {
+		t.Fatalf("pollForToken() error = %v", err)
+	}
+	if creds.Token != "lane_token" {
+		t.Errorf("pollForToken() = %q, want lane_token", creds.Token)
+	}
+
+	if creds.RefreshToken != "lane_refresh" || creds.ExpiresAt == nil {
+		t.Errorf("pollForToken() dropped the refresh details: %+v", creds)
+	}
+
+	want := []time.Duration{3 * time.Second, 3 * time.Second, 8 * time.Second, 8 * time.Second, 15 * time.Second}
+	if len(*waits) != len(want) {
+		t.Fatalf("waits = %v, want %v", *waits, want)
+	}
+	for i := range want {
+		if (*waits)[i] != want[i] {
+			t.Errorf("waits = %v, want %v", *waits, want)
+			break
+		}
+	}
+}
+
+func TestPollForTokenDefaults(t *testing.T) {
+	waits := testutil.RecordSleeps(t, &sleep)
+	server := fakePoller(t, cliAuthResponse{Token: "lane_token"})
+
+	if _, err := pollForToken(context.Background(), server.URL, cliAuthResponse{Code: "abc123"}); err != nil {
+		t.Fatalf("pollForToken() error = %v", err)
+	}
+	if len(*waits) != 1 || (*waits)[0] != defaultPollInterval {
+		t.Errorf("waits = %v, want [%v]", *waits, defaultPollInterval)
+	}
+}
+
+func TestPollForTokenErrors(t *testing.T) {
+	testutil.RecordSleeps(t, &sleep)
+
+	tests := []struct {
+		error string
+		want  string
+	}{
+		{"expired_token", "expired"},
+		{"access_denied", "denied"},
+		{"Something went wrong", "Something went wrong"},
+	}
+
+	for _, tt := range tests {
+		server := fakePoller(t, cliAuthResponse{Error: tt.error})
+		_, err := pollForToken(context.Background(), server.URL, cliAuthResponse{Code: "abc123"})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("pollForToken() with %q error = %v, want %q", tt.error, err, tt.want)
+		}
+	}
+}
+
+func TestPollForTokenCancelled(t *testing.T) {
+	server := fakePoller(t, cliAuthResponse{Error: "authorization_pending"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := pollForToken(ctx, server.URL, cliAuthResponse{Code: "abc123", Interval: 60})
+	if !cancelled(err) {
+		t.Errorf("pollForToken() error = %v, want context.Canceled", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("pollForToken() kept waiting after cancellation")
+	}
+}
+
+func TestDeviceCodeLink(t *testing.T) {
+	authURL := "https://lane.example.com/auth/cli?code=secret_device_code"
+
+	resp := cliAuthResponse{Code: "secret_device_code", UserCode: "WDJB-MJHT", VerificationURL: "https://lane.example.com/device"}
+	if got := deviceCodeLink(resp, authURL); got != resp.VerificationURL {
+		t.Errorf("deviceCodeLink() = %q, want the verification page", got)
+	}
+
+	resp = cliAuthResponse{Code: "secret_device_code"}
+	if got := deviceCodeLink(resp, authURL); got != authURL {
+		t.Errorf("deviceCodeLink() without a user code = %q, want %q", got, authURL)
+	}
+}
+
+func TestReadToken(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"lane_abc123\n", "lane_abc123", false},
+		{"  lane_abc123  \r\n", "lane_abc123", false},
+		{"", "", true},
+		{"\n\n", "", true},
+		{"lane_abc lane_def\n", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := readToken(strings.NewReader(tt.input))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("readToken(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("readToken(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRunTokenLogin(t *testing.T) {
+	setTempHome(t)
+	t.Setenv(config.EnvCredentialStore, config.StoreFile)
+	t.Setenv(config.EnvAuthToken, "")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer good_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(api.ErrorResponse{Message: "Invalid token"})
+			return
+		}
+		json.NewEncoder(w).Encode(api.UserResponse{ID: "user_1", Email: "jane@example.com"})
+	}))
+	defer server.Close()
+	t.Setenv(config.EnvAPIURL, server.URL)
+
+	original := stdin
+	defer func() { stdin = original }()
+
+	stdin = strings.NewReader("bad_token\n")
+	if err := runTokenLogin(context.Background()); err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Errorf("runTokenLogin(context.Background()) with a bad token error = %v, want rejected", err)
+	}
+	if config.IsLoggedIn() {
+		t.Fatal("a rejected token was saved")
+	}
+
+	stdin = strings.NewReader("good_token\n")
+	if err := runTokenLogin(context.Background()); err != nil {
+		t.Fatalf("runTokenLogin(context.Background()) error = %v", err)
+	}
+	if got, _ := config.GetAuthToken(); got != "good_token" {
+		t.Errorf("saved token = %q, want good_token", got)
+	}
+}
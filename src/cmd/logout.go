@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 
+	"github.com/forrestcai35/lane/internal/api"
 	"github.com/forrestcai35/lane/internal/config"
 	"github.com/forrestcai35/lane/internal/ui"
 	"github.com/spf13/cobra"
@@ -11,24 +16,82 @@ import (
 var logoutCmd = &cobra.Command{
 	Use:   "logout",
 	Short: "Log out of Lane",
-	Long:  `Removes your stored authentication token.`,
-	RunE:  runLogout,
+	Long: `Revokes your token on the Lane server, so it can no longer be used
+even if it has leaked, then removes it from the credential store.
+
+With --local-only, the token is only removed locally.
+
+Logout only touches the stored token. A token in $LANE_TOKEN is left alone;
+unset the variable to stop using it.`,
+	RunE: runLogout,
 }
 
+var localOnly bool
+
 func init() {
+	logoutCmd.Flags().BoolVar(&localOnly, "local-only", false, "Remove the local token without revoking it on the server")
 	rootCmd.AddCommand(logoutCmd)
 }
 
 func runLogout(cmd *cobra.Command, args []string) error {
-	if !config.IsLoggedIn() {
+	// Work on the stored token even when LANE_TOKEN is set, so an env token
+	// (e.g. a CI token) is never revoked in its place
+	creds, err := config.GetStoredCredentials()
+	if errors.Is(err, config.ErrNotLoggedIn) {
 		fmt.Println(ui.Subtle.Render("You're not logged in."))
+		warnEnvToken()
 		return nil
 	}
+	if err != nil {
+		return fmt.Errorf("failed to logout: %w", err)
+	}
+
+	store, err := config.GetCredentialStore()
+	if err != nil {
+		return fmt.Errorf("failed to logout: %w", err)
+	}
+
+	if !localOnly {
+		if err := revokeToken(cmd.Context(), creds); err != nil {
+			fmt.Println(ui.FormatError("Could not revoke the token on the server: " + err.Error()))
+			fmt.Println(ui.Subtle.Render("Nothing was removed. Use --local-only to remove the local token anyway."))
+			return fmt.Errorf("failed to logout: %w", err)
+		}
+		fmt.Println(ui.FormatSuccess("Token revoked on the server"))
+	}
 
 	if err := config.DeleteAuthToken(); err != nil {
 		return fmt.Errorf("failed to logout: %w", err)
 	}
 
 	fmt.Println(ui.FormatSuccess("✓ Logged out"))
+	fmt.Println(ui.Subtle.Render("Token removed from " + describeStore(store)))
+	warnEnvToken()
 	return nil
 }
+
+// revokeToken invalidates a stored login on the server
+func revokeToken(ctx context.Context, creds config.Credentials) error {
+	return api.NewClientWithCredentials(creds).RevokeTokenContext(ctx)
+}
+
+// warnEnvToken points out that LANE_TOKEN still logs commands in
+func warnEnvToken() {
+	if os.Getenv(config.EnvAuthToken) != "" {
+		fmt.Println(ui.Subtle.Render("$" + config.EnvAuthToken + " is set and still used; unset it to stop using that token."))
+	}
+}
+
+// describeStore names where a credential store keeps the token
+func describeStore(store config.CredentialStore) string {
+	switch store.Name() {
+	case config.StoreKeyring:
+		return "the system keyring"
+	case config.StoreHelper:
+		return "credential helper '" + config.GetCredentialHelper() + "'"
+	case config.StoreEncryptedFile:
+		return filepath.Join(config.GetProfileDir(), "token.enc") + " (encrypted)"
+	default:
+		return filepath.Join(config.GetProfileDir(), "token")
+	}
+}
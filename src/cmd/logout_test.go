@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/forrestcai35/lane/internal/config"
+)
+
+func TestRunLogoutWithEnvToken(t *testing.T) {
+	setTempHome(t)
+	t.Setenv(config.EnvCredentialStore, config.StoreFile)
+	t.Setenv(config.EnvAuthToken, "")
+
+	var revoked []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/auth/revoke" {
+			revoked = append(revoked, r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	t.Setenv(config.EnvAPIURL, server.URL)
+
+	if err := config.SaveAuthToken("stored_token"); err != nil {
+		t.Fatalf("SaveAuthToken() error = %v", err)
+	}
+	t.Setenv(config.EnvAuthToken, "ci_token")
+
+	logoutCmd.SetContext(context.Background())
+	if err := runLogout(logoutCmd, nil); err != nil {
+		t.Fatalf("runLogout() error = %v", err)
+	}
+
+	if len(revoked) != 1 || revoked[0] != "Bearer stored_token" {
+		t.Errorf("revoked %v, want only the stored token", revoked)
+	}
+	if _, err := config.GetStoredCredentials(); !errors.Is(err, config.ErrNotLoggedIn) {
+		t.Errorf("GetStoredCredentials() after logout error = %v, want ErrNotLoggedIn", err)
+	}
+}
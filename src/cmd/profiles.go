@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/forrestcai35/lane/internal/currency"
+	"github.com/forrestcai35/lane/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Profile flags
+	profileAPIURL   string
+	profileCurrency string
+)
+
+var profilesCmd = &cobra.Command{
+	Use:     "profiles",
+	Aliases: []string{"profile"},
+	Short:   "Manage profiles for multiple accounts",
+	Long: `Profiles let you invoice from more than one Lane account. Each profile has
+its own login, API URL, default currency and client address book.
+
+The active profile is chosen by --profile, then $LANE_PROFILE, then the
+profile selected with 'lane profiles use'. Without any of these the
+"default" profile is used.`,
+	Example: `  lane login --profile work
+  lane 500 --client acme --desc "Consulting" --profile work
+  lane profiles use work`,
+}
+
+var profilesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles",
+	Args:  cobra.NoArgs,
+	RunE:  runProfilesList,
+}
+
+var profilesUseCmd = &cobra.Command{
+	Use:               "use <name>",
+	Short:             "Switch the current profile",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfileArg,
+	RunE:              runProfilesUse,
+}
+
+var profilesSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Create a profile or change its settings",
+	Long: `Create a profile or change its settings. Only the flags you pass are
+changed; pass an empty value to go back to the default.`,
+	Example: `  lane profiles set work --currency eur
+  lane profiles set staging --api-url https://staging.example.com`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfileArg,
+	RunE:              runProfilesSet,
+}
+
+var profilesRemoveCmd = &cobra.Command{
+	Use:               "remove <name>",
+	Aliases:           []string{"rm"},
+	Short:             "Remove a profile with its login and address book",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfileArg,
+	RunE:              runProfilesRemove,
+}
+
+func init() {
+	profilesSetCmd.Flags().StringVar(&profileAPIURL, "api-url", "", "Lane API URL")
+	profilesSetCmd.Flags().StringVar(&profileCurrency, "currency", "", "Default currency code")
+	profilesSetCmd.RegisterFlagCompletionFunc("currency", completeCurrency)
+	profilesRemoveCmd.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "Skip the confirmation prompt")
+
+	profilesCmd.AddCommand(profilesListCmd)
+	profilesCmd.AddCommand(profilesUseCmd)
+	profilesCmd.AddCommand(profilesSetCmd)
+	profilesCmd.AddCommand(profilesRemoveCmd)
+	rootCmd.AddCommand(profilesCmd)
+}
+
+// selectProfile applies --profile and checks the active profile exists.
+// 'lane login' and the profiles commands may name a new profile.
+func selectProfile(cmd *cobra.Command, args []string) error {
+	if profileName != "" {
+		config.SetProfile(profileName)
+	}
+
+	name := config.GetProfile()
+	if err := config.ValidateProfileName(name); err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	if cmd == loginCmd || cmd.Parent() == profilesCmd {
+		return nil
+	}
+
+	exists, err := config.ProfileExists(name)
+	if err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+	if !exists {
+		err := fmt.Errorf("no profile named %q. Create it with 'lane login --profile %s'", name, name)
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+	return nil
+}
+
+func runProfilesList(cmd *cobra.Command, args []string) error {
+	profiles, err := config.ListProfiles()
+	if err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	active := config.GetProfile()
+	rows := make([][]string, 0, len(profiles))
+	for _, p := range profiles {
+		name := "  " + p.Name
+		if p.Name == active {
+			name = "* " + p.Name
+		}
+
+		apiURL := p.APIURL
+		if apiURL == "" {
+			apiURL = ui.Subtle.Render(config.DefaultAPIURL)
+		}
+		code := strings.ToUpper(p.Currency)
+		if code == "" {
+			code = ui.Subtle.Render(strings.ToUpper(config.DefaultCurrency))
+		}
+
+		rows = append(rows, []string{name, apiURL, code})
+	}
+
+	fmt.Println(ui.Table([]string{"Profile", "API URL", "Currency"}, rows))
+	return nil
+}
+
+func runProfilesUse(cmd *cobra.Command, args []string) error {
+	name := strings.ToLower(args[0])
+	if err := config.UseProfile(name); err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	fmt.Println(ui.FormatSuccess("Now using profile " + name))
+	return nil
+}
+
+func runProfilesSet(cmd *cobra.Command, args []string) error {
+	name := strings.ToLower(args[0])
+	if err := config.ValidateProfileName(name); err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	p := findProfile(name)
+	if p == nil {
+		p = &config.Profile{Name: name}
+	}
+
+	if err := applyProfileFlags(cmd, p); err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return usageError(err)
+	}
+
+	if err := config.SaveProfile(*p); err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	fmt.Println(ui.FormatSuccess("Saved profile " + name))
+	return nil
+}
+
+func runProfilesRemove(cmd *cobra.Command, args []string) error {
+	name := strings.ToLower(args[0])
+	if findProfile(name) == nil {
+		err := fmt.Errorf("no profile named %q (see 'lane profiles list')", name)
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	if !skipConfirm {
+		if err := confirm(fmt.Sprintf("Remove profile %s, its login and its address book?", name)); err != nil {
+			fmt.Println(ui.FormatError(err.Error()))
+			return err
+		}
+	}
+
+	if err := config.RemoveProfile(name); err != nil {
+		fmt.Println(ui.FormatError(err.Error()))
+		return err
+	}
+
+	fmt.Println(ui.FormatSuccess("Removed profile " + name))
+	return nil
+}
+
+// findProfile returns the saved profile with the given name
+func findProfile(name string) *config.Profile {
+	profiles, err := config.ListProfiles()
+	if err != nil {
+		return nil
+	}
+	for i := range profiles {
+		if profiles[i].Name == name {
+			return &profiles[i]
+		}
+	}
+	return nil
+}
+
+// applyProfileFlags copies the profile flags that were passed onto a profile
+func applyProfileFlags(cmd *cobra.Command, p *config.Profile) error {
+	flags := cmd.Flags()
+
+	if flags.Changed("api-url") {
+		p.APIURL = strings.TrimRight(profileAPIURL, "/")
+		if p.APIURL != "" && !strings.HasPrefix(p.APIURL, "http://") && !strings.HasPrefix(p.APIURL, "https://") {
+			return fmt.Errorf("invalid API URL %q (expected http:// or https://)", profileAPIURL)
+		}
+	}
+
+	if flags.Changed("currency") {
+		p.Currency = ""
+		if profileCurrency != "" {
+			cur, ok := currency.Lookup(profileCurrency)
+			if !ok {
+				return fmt.Errorf("unknown currency: %s", profileCurrency)
+			}
+			p.Currency = strings.ToLower(cur.Code)
+		}
+	}
+
+	return nil
+}
+
+// completeProfile completes profile names for --profile
+func completeProfile(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	profiles, err := config.ListProfiles()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, 0, len(profiles))
+	for _, p := range profiles {
+		names = append(names, p.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProfileArg completes the profile name argument
+func completeProfileArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeProfile(cmd, args, toComplete)
+}
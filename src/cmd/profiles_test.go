@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func TestApplyProfileFlags(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringVar(&profileAPIURL, "api-url", "", "")
+		cmd.Flags().StringVar(&profileCurrency, "currency", "", "")
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatalf("ParseFlags() error = %v", err)
+		}
+		return cmd
+	}
+
+	p := config.Profile{Name: "work", Currency: "gbp"}
+	if err := applyProfileFlags(newCmd("--api-url", "https://lane.example.com/"), &p); err != nil {
+		t.Fatalf("applyProfileFlags() error = %v", err)
+	}
+	if p.APIURL != "https://lane.example.com" || p.Currency != "gbp" {
+		t.Errorf("unexpected profile: %+v", p)
+	}
+
+	if err := applyProfileFlags(newCmd("--currency", "EUR", "--api-url", ""), &p); err != nil {
+		t.Fatalf("applyProfileFlags() error = %v", err)
+	}
+	if p.APIURL != "" || p.Currency != "eur" {
+		t.Errorf("unexpected profile: %+v", p)
+	}
+
+	for _, args := range [][]string{{"--currency", "xyz"}, {"--api-url", "lane.example.com"}} {
+		if err := applyProfileFlags(newCmd(args...), &p); err == nil {
+			t.Errorf("applyProfileFlags(%v) should fail", args)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/forrestcai35/lane/internal/ui"
+	"golang.org/x/term"
+)
+
+var (
+	// stdin is where prompts read answers from (replaced in tests)
+	stdin io.Reader = os.Stdin
+
+	// stdinIsTerminal reports whether someone can answer a confirmation
+	// (replaced in tests)
+	stdinIsTerminal = func() bool { return term.IsTerminal(int(os.Stdin.Fd())) }
+
+	// answers buffers stdin for every prompt. A reader per prompt would read
+	// ahead and swallow piped answers meant for the next one.
+	answers     *bufio.Reader
+	answersFrom io.Reader
+)
+
+// errDeclined is returned when the user doesn't confirm an action
+var errDeclined = errors.New("not confirmed, nothing was changed")
+
+func init() {
+	// Without a terminal, the encrypted-file store asks for LANE_PASSPHRASE
+	if stdinIsTerminal() {
+		config.PassphrasePrompt = promptPassphrase
+	}
+}
+
+// answerReader returns the shared buffered reader over stdin
+func answerReader() *bufio.Reader {
+	if answers == nil || answersFrom != stdin {
+		answers = bufio.NewReader(stdin)
+		answersFrom = stdin
+	}
+	return answers
+}
+
+// confirm asks a yes/no question and returns nil only for "y" or "yes".
+// Any other answer or closed input returns errDeclined. Without a terminal
+// it fails with a usage error instead of guessing; scripts pass --yes.
+func confirm(question string) error {
+	if !stdinIsTerminal() {
+		return usageError(errors.New("confirmation required but there is no terminal to ask on. Pass --yes to skip it"))
+	}
+
+	fmt.Print(ui.Label.Render(question + " [y/N] "))
+
+	answer, err := answerReader().ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Println()
+		return errDeclined
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return errDeclined
+	}
+}
+
+// choose asks the user to pick one of options, accepting the full option or
+// its first letter. It re-asks on invalid input and fails if input runs out
+// before a valid answer.
+func choose(question string, options []string) (string, error) {
+	labels := make([]string, len(options))
+	for i, o := range options {
+		labels[i] = "[" + o[:1] + "]" + o[1:]
+	}
+
+	reader := answerReader()
+	for {
+		fmt.Print(ui.Label.Render(question + " " + strings.Join(labels, "/") + " "))
+
+		answer, err := reader.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		for _, o := range options {
+			if answer != "" && (answer == o || answer == o[:1]) {
+				return o, nil
+			}
+		}
+
+		if err != nil {
+			fmt.Println()
+			return "", fmt.Errorf("no answer to %q: input ended", question)
+		}
+	}
+}
+
+// promptPassphrase reads the credential passphrase without echoing it.
+// When confirm is set the passphrase is asked for twice.
+func promptPassphrase(confirm bool) (string, error) {
+	fd := int(os.Stdin.Fd())
+
+	question := "Passphrase for the Lane token:"
+	if confirm {
+		question = "Choose a passphrase to encrypt the Lane token:"
+	}
+	fmt.Print(ui.Label.Render(question + " "))
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("could not read passphrase: %w", err)
+	}
+
+	if confirm {
+		fmt.Print(ui.Label.Render("Repeat passphrase: "))
+		again, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			return "", fmt.Errorf("could not read passphrase: %w", err)
+		}
+		if string(again) != string(passphrase) {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+
+	return string(passphrase), nil
+}
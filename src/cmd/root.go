@@ -1,11 +1,21 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/forrestcai35/lane/internal/api"
 	"github.com/forrestcai35/lane/internal/clipboard"
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/forrestcai35/lane/internal/currency"
 	"github.com/forrestcai35/lane/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -15,17 +25,24 @@ var (
 	Version = "0.1.0"
 
 	// Flags
-	clientName  string
-	clientEmail string
-	description string
-	currency    string
-	sendEmail   bool
-	noCopy      bool
+	clientName   string
+	clientEmail  string
+	description  string
+	currencyCode string
+	sendEmail    bool
+	noCopy       bool
+	locale       string
+	itemSpecs    []string
+	draft        bool
+	dueDate      string
+	netDays      int
+	hours        string
+	profileName  string
 )
 
 // rootCmd represents the base command
 var rootCmd = &cobra.Command{
-	Use:   "lane <amount>",
+	Use:   "lane [amount]",
 	Short: "Generate Stripe invoices instantly",
 	Long: ui.Logo.Render("Lane") + `
 The fastest way to generate a Stripe invoice from the terminal.
@@ -35,44 +52,263 @@ The fastest way to generate a Stripe invoice from the terminal.
   lane 500 --client "Apple" --desc "Work" # Create invoice`,
 	Example: `  lane 100 --client "Acme Corp" --desc "Consulting"
   lane 500 --client "Apple" --desc "Web Design" --email "tim@apple.com" --send
-  lane 2500 --desc "Logo Design" --currency eur`,
-	Args: cobra.ExactArgs(1),
-	RunE: runInvoice,
+  lane 2500 --desc "Logo Design" --currency eur
+  lane "1.234,56" --desc "Retainer" --currency eur --locale de
+  lane --desc "March" --item "Design:3x150" --item "Hosting:20@20%"
+  lane 12000 --client "Acme Corp" --desc "Q3 retainer" --draft
+  lane 800 --client "Acme Corp" --desc "Support" --due "next friday"
+  lane 800 --client "Acme Corp" --desc "Support" --net 30
+  lane 500 --client acme --desc "Consulting"
+  lane --hours 12.5 --desc "Sprint 4"  # Rate and client from .lane.yaml`,
+	Args:              cobra.MaximumNArgs(1),
+	PersistentPreRunE: prepare,
+	RunE:              runInvoice,
 }
 
-// Execute runs the root command
+// cancelGrace is how long a command gets to wind down after Ctrl-C before
+// Lane exits anyway, e.g. when it is blocked on a prompt
+const cancelGrace = 3 * time.Second
+
+// Execute runs the root command. Ctrl-C or SIGTERM cancels the command's
+// context; a second Ctrl-C exits straight away.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+		}
+
+		stop() // Restore the default handler for a second Ctrl-C
+		select {
+		case <-done:
+		case <-time.After(cancelGrace):
+			fmt.Println()
+			fmt.Println(ui.Subtle.Render("Cancelled."))
+			os.Exit(exitCancelled)
+		}
+	}()
+
+	defer closeDebugLog()
+
+	markUsageErrors(rootCmd)
+	return rootCmd.ExecuteContext(ctx)
+}
+
+// cancelled reports whether err comes from the command's context being
+// cancelled by Ctrl-C
+func cancelled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
+
+// reportCancelled prints msg after Ctrl-C stopped cmd, instead of the raw
+// error and usage cobra would print
+func reportCancelled(cmd *cobra.Command, msg string) {
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	fmt.Println()
+	fmt.Println(ui.Subtle.Render(msg))
+}
+
+// prepare runs before every command: it checks the flags, selects the
+// profile, checks the config file and applies the theme
+func prepare(cmd *cobra.Command, args []string) error {
+	// Cobra checks these after PersistentPreRunE; check them first so
+	// they count as usage errors
+	if err := cmd.ValidateRequiredFlags(); err != nil {
+		return usageError(err)
+	}
+	if err := cmd.ValidateFlagGroups(); err != nil {
+		return usageError(err)
+	}
+
+	if err := selectProfile(cmd, args); err != nil {
+		return err
+	}
+
+	// 'lane config' has to work with a broken config file to fix it
+	if !isConfigCmd(cmd) {
+		if err := config.CheckSettings(); err != nil {
+			fmt.Println(formatError(err))
+			return err
+		}
+	}
+
+	ui.SetTheme(config.GetTheme())
+
+	if err := setupDebug(cmd); err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+	return nil
 }
 
 func init() {
-	rootCmd.Flags().StringVarP(&clientName, "client", "c", "", "Client name")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Profile to use (default: $LANE_PROFILE or the current profile)")
+	rootCmd.RegisterFlagCompletionFunc("profile", completeProfile)
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Log API requests and responses to stderr, with secrets redacted (default: $LANE_DEBUG)")
+	rootCmd.PersistentFlags().BoolVar(&logFile, "log-file", false, "Write the --debug log to ~/.lane/logs/lane.log instead of stderr")
+
+	rootCmd.Flags().StringVarP(&clientName, "client", "c", "", "Client name or address book alias")
 	rootCmd.Flags().StringVarP(&clientEmail, "email", "e", "", "Client email address")
 	rootCmd.Flags().StringVarP(&description, "desc", "d", "", "Invoice description (required)")
-	rootCmd.Flags().StringVar(&currency, "currency", "usd", "Currency code (usd, eur, gbp, etc.)")
-	rootCmd.Flags().BoolVar(&sendEmail, "send", false, "Send invoice via email (requires --email)")
+	rootCmd.Flags().StringVar(&currencyCode, "currency", "", "Currency code (usd, eur, gbp, etc.; default: the profile's currency or usd)")
+	rootCmd.Flags().BoolVar(&sendEmail, "send", false, "Send invoice via email (requires --email; default: the send setting)")
 	rootCmd.Flags().BoolVar(&noCopy, "no-copy", false, "Don't copy link to clipboard")
+	rootCmd.Flags().BoolVar(&draft, "draft", false, "Create a draft for review instead of finalizing")
+	rootCmd.Flags().StringVar(&dueDate, "due", "", "Due date (2026-11-30, +14d, \"next friday\", tomorrow)")
+	rootCmd.Flags().IntVar(&netDays, "net", 0, "Payment terms in days (e.g. 30 for net 30)")
+	rootCmd.MarkFlagsMutuallyExclusive("due", "net")
+	rootCmd.Flags().StringVar(&locale, "locale", "", "Number format for amounts (en: 1,234.56, de: 1.234,56; default: the locale setting)")
+	rootCmd.Flags().StringArrayVarP(&itemSpecs, "item", "i", nil, "Line item as DESCRIPTION:[QTYx]PRICE[@TAX%] (repeatable)")
+	rootCmd.Flags().StringVar(&hours, "hours", "", "Hours worked, billed at the rate in .lane.yaml (replaces amount)")
 
 	rootCmd.MarkFlagRequired("desc")
+	rootCmd.RegisterFlagCompletionFunc("currency", completeCurrency)
 
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 }
 
 func runInvoice(cmd *cobra.Command, args []string) error {
-	// Parse amount
-	amountCents, err := parseAmount(args[0])
+	flags := cmd.Flags()
+
+	req := api.InvoiceRequest{
+		ClientName:  clientName,
+		ClientEmail: clientEmail,
+		Description: description,
+		Draft:       draft,
+	}
+
+	// Fill in defaults from the project's .lane.yaml; explicit flags win
+	project, err := config.FindProject()
 	if err != nil {
-		fmt.Println(ui.FormatError(err.Error()))
+		fmt.Println(formatError(err))
 		return err
 	}
+	keepEmail := flags.Changed("email")
+	if project != nil {
+		keepEmail = applyProject(cmd, &req, project)
+	}
+
+	// Fill in details from the address book; explicit flags win
+	code := currencyCode
+	if code == "" {
+		code = config.GetDefaultCurrency()
+	}
+	var net *int
+	if flags.Changed("net") {
+		net = &netDays
+	}
+	if req.ClientName != "" {
+		entry, err := config.FindClient(req.ClientName)
+		if err != nil {
+			fmt.Println(formatError(err))
+			return err
+		}
+		if entry != nil {
+			applyClient(&req, entry, keepEmail)
+			projectCurrency := project != nil && project.Currency != ""
+			if !flags.Changed("currency") && !projectCurrency && entry.Currency != "" {
+				code = entry.Currency
+			}
+			if net == nil && dueDate == "" {
+				net = entry.Terms
+			}
+		}
+	}
+
+	// Validate currency before anything reaches the API
+	cur, ok := currency.Lookup(code)
+	if !ok {
+		err := fmt.Errorf("unknown currency: %s", code)
+		fmt.Println(formatError(err))
+		return usageError(err)
+	}
+	req.Currency = strings.ToLower(cur.Code)
+
+	// Parse amount, hours or line items
+	sources := 0
+	for _, given := range []bool{len(args) == 1, len(itemSpecs) > 0, flags.Changed("hours")} {
+		if given {
+			sources++
+		}
+	}
+	switch {
+	case sources > 1:
+		err := fmt.Errorf("use only one of an amount, --item or --hours")
+		fmt.Println(formatError(err))
+		return usageError(err)
+	case flags.Changed("hours"):
+		amount, err := hourlyAmount(project, hours, cur, amountLocale(locale))
+		if err != nil {
+			fmt.Println(formatError(err))
+			return usageError(err)
+		}
+		req.Amount = amount
+	case len(args) == 1:
+		amount, err := parseAmount(args[0], cur, amountLocale(locale))
+		if err != nil {
+			fmt.Println(formatError(err))
+			return usageError(err)
+		}
+		req.Amount = amount
+	case len(itemSpecs) > 0:
+		for _, spec := range itemSpecs {
+			item, err := parseItem(spec, cur, amountLocale(locale))
+			if err != nil {
+				fmt.Println(formatError(err))
+				return usageError(err)
+			}
+			req.LineItems = append(req.LineItems, item)
+		}
+		subtotal, tax, err := itemTotals(req.LineItems)
+		if err != nil {
+			fmt.Println(formatError(err))
+			return usageError(err)
+		}
+		req.Amount = subtotal + tax
+	default:
+		err := fmt.Errorf("an amount, --hours or at least one --item is required")
+		fmt.Println(formatError(err))
+		return usageError(err)
+	}
 
-	// Validate email flags
-	if sendEmail && clientEmail == "" {
+	// Resolve due date from --due, --net or the client's default terms
+	due, err := resolveDueDate(dueDate, net, time.Now())
+	if err != nil {
+		fmt.Println(formatError(err))
+		return usageError(err)
+	}
+	req.DueDate = due
+
+	// Validate email flags. Without --send, invoices are emailed when the
+	// send setting is on and there is an address to send them to.
+	req.SendEmail = sendEmail
+	if !flags.Changed("send") {
+		req.SendEmail = config.GetSendByDefault() && req.ClientEmail != "" && !draft
+	}
+	if req.SendEmail && req.ClientEmail == "" {
 		err := fmt.Errorf("--send requires --email flag")
-		fmt.Println(ui.FormatError(err.Error()))
-		return err
+		fmt.Println(formatError(err))
+		return usageError(err)
+	}
+	if req.SendEmail && draft {
+		err := fmt.Errorf("--send can't be used with --draft (send it later with 'lane invoices send <id>')")
+		fmt.Println(formatError(err))
+		return usageError(err)
 	}
 
+	return createInvoice(cmd, req, cur, copyEnabled(noCopy))
+}
+
+// createInvoice sends an invoice to the API and prints the result box,
+// copying the payment link when copyLink is set
+func createInvoice(cmd *cobra.Command, req api.InvoiceRequest, cur currency.Currency, copyLink bool) error {
 	// Print header
 	fmt.Println()
 	fmt.Println(ui.Logo.Render("⚡ Lane"))
@@ -81,28 +317,30 @@ func runInvoice(cmd *cobra.Command, args []string) error {
 	fmt.Println(ui.FormatStep("Connecting..."))
 	client, err := api.NewClient()
 	if err != nil {
-		fmt.Println(ui.FormatError(err.Error()))
+		fmt.Println(formatError(err))
 		return err
 	}
 
 	// Create the invoice via API
 	fmt.Println(ui.FormatStep("Creating invoice..."))
-	result, err := client.CreateInvoice(api.InvoiceRequest{
-		Amount:      amountCents,
-		Currency:    strings.ToLower(currency),
-		ClientName:  clientName,
-		ClientEmail: clientEmail,
-		Description: description,
-		SendEmail:   sendEmail,
-	})
+	result, err := client.CreateInvoiceContext(cmd.Context(), req)
+	if cancelled(err) {
+		reportCancelled(cmd, "Cancelled. The invoice may still have been created; check 'lane invoices list' before trying again.")
+		return err
+	}
 	if err != nil {
-		fmt.Println(ui.FormatError(err.Error()))
+		fmt.Println(formatError(err))
 		return err
 	}
 
+	if req.Draft {
+		printDraft(req, result.ID, cur)
+		return nil
+	}
+
 	// Copy to clipboard
 	clipboardStatus := ""
-	if !noCopy && clipboard.IsSupported() {
+	if copyLink && clipboard.IsSupported() {
 		if err := clipboard.Copy(result.PaymentLink); err != nil {
 			clipboardStatus = ui.Subtle.Render("(clipboard unavailable)")
 		} else {
@@ -115,26 +353,15 @@ func runInvoice(cmd *cobra.Command, args []string) error {
 
 	output.WriteString(ui.FormatSuccess("Invoice created!"))
 	output.WriteString("\n\n")
-
-	// Details
-	if clientName != "" {
-		output.WriteString(ui.FormatLabel("Client", clientName))
-		output.WriteString("\n")
-	}
-	if clientEmail != "" {
-		output.WriteString(ui.FormatLabel("Email", clientEmail))
-		output.WriteString("\n")
-	}
-	output.WriteString(ui.FormatLabel("Description", description))
-	output.WriteString("\n")
-	output.WriteString(ui.FormatLabel("Amount", ui.FormatAmount(amountCents)))
-	output.WriteString("\n")
+	output.WriteString(formatRequestDetails(req, cur))
 	output.WriteString(ui.FormatLabel("Invoice", result.ID))
+	output.WriteString("\n")
+	output.WriteString(ui.Subtle.Render("View details: lane invoices show " + result.ID))
 	output.WriteString("\n\n")
 
 	// Status messages
 	if result.EmailSent {
-		output.WriteString(ui.FormatSuccess("✓ Email sent to " + clientEmail))
+		output.WriteString(ui.FormatSuccess("✓ Email sent to " + req.ClientEmail))
 		output.WriteString("\n\n")
 	}
 
@@ -150,21 +377,269 @@ func runInvoice(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// parseAmount converts a string amount to cents
-func parseAmount(s string) (int64, error) {
+// printDraft prints the result box for a newly created draft
+func printDraft(req api.InvoiceRequest, id string, cur currency.Currency) {
+	var output strings.Builder
+
+	output.WriteString(ui.FormatSuccess("Draft created!"))
+	output.WriteString("\n\n")
+	output.WriteString(formatRequestDetails(req, cur))
+	output.WriteString(ui.FormatLabel("Draft", id))
+	output.WriteString("\n\n")
+
+	// Next steps
+	output.WriteString(ui.Label.Render("Next steps:"))
+	output.WriteString("\n")
+	output.WriteString(ui.Subtle.Render("  lane invoices show " + id))
+	output.WriteString("\n")
+	output.WriteString(ui.Subtle.Render("  lane invoices edit " + id + " --desc \"...\""))
+	output.WriteString("\n")
+	output.WriteString(ui.Subtle.Render("  lane invoices send " + id))
+
+	fmt.Println(ui.ResultBox.Render(output.String()))
+	fmt.Println()
+}
+
+// formatRequestDetails renders the client, description and amount of a
+// new invoice, ending with a newline
+func formatRequestDetails(req api.InvoiceRequest, cur currency.Currency) string {
+	var output strings.Builder
+
+	if req.ClientName != "" {
+		output.WriteString(ui.FormatLabel("Client", req.ClientName))
+		output.WriteString("\n")
+	}
+	if req.ClientEmail != "" {
+		output.WriteString(ui.FormatLabel("Email", req.ClientEmail))
+		output.WriteString("\n")
+	}
+	if len(req.CCEmails) > 0 {
+		output.WriteString(ui.FormatLabel("CC", strings.Join(req.CCEmails, ", ")))
+		output.WriteString("\n")
+	}
+	if req.Description != "" {
+		output.WriteString(ui.FormatLabel("Description", req.Description))
+		output.WriteString("\n")
+	}
+	if req.DueDate != nil {
+		output.WriteString(ui.FormatLabel("Due", req.DueDate.Format("Mon, Jan 2, 2006")))
+		output.WriteString("\n")
+	}
+	if len(req.LineItems) > 0 {
+		output.WriteString("\n")
+		output.WriteString(formatItems(req.LineItems, cur))
+		output.WriteString("\n\n")
+	} else {
+		output.WriteString(ui.FormatLabel("Amount", ui.FormatAmount(req.Amount, cur)))
+		output.WriteString("\n")
+	}
+
+	return output.String()
+}
+
+// applyProject fills in the client, email and description prefix from a
+// .lane.yaml file. It reports whether the email is fixed, so the address
+// book doesn't replace it.
+func applyProject(cmd *cobra.Command, req *api.InvoiceRequest, p *config.Project) bool {
+	flags := cmd.Flags()
+
+	// The project's email belongs to the project's client
+	keepEmail := flags.Changed("email")
+	if !flags.Changed("client") && p.Client != "" {
+		req.ClientName = p.Client
+		if !keepEmail && p.Email != "" {
+			req.ClientEmail = p.Email
+			keepEmail = true
+		}
+	}
+
+	if p.DescriptionPrefix != "" {
+		req.Description = strings.TrimSpace(p.DescriptionPrefix + " " + req.Description)
+	}
+	return keepEmail
+}
+
+// hourlyAmount bills hours at the project's rate. Hours are written in
+// locale's number format; the rate uses the en format, like manifests. The
+// total is computed exactly and rounded half up to the currency's minor unit.
+func hourlyAmount(p *config.Project, hours string, cur currency.Currency, locale string) (int64, error) {
+	if p == nil || p.Rate == "" {
+		return 0, fmt.Errorf("--hours needs a rate in %s", config.ProjectFile)
+	}
+
+	h, scale, err := parseDecimal(strings.TrimSpace(hours), localeFormat(locale))
+	if err != nil {
+		return 0, fmt.Errorf("invalid hours: %s", hours)
+	}
+	if h <= 0 {
+		return 0, fmt.Errorf("hours must be greater than zero")
+	}
+
+	rate, err := parseAmount(p.Rate, cur, "en")
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate in %s: %w", p.Path, err)
+	}
+
+	// rate × h / 10^scale, rounded half up
+	if rate > math.MaxInt64/h {
+		return 0, fmt.Errorf("amount is too large")
+	}
+	unit := pow10(scale)
+	total, rem := rate*h/unit, rate*h%unit
+	if rem*2 >= unit {
+		total++
+	}
+	return total, nil
+}
+
+// copyEnabled reports whether payment links should go to the clipboard:
+// not with --no-copy, or when the clipboard setting is off
+func copyEnabled(noCopy bool) bool {
+	return !noCopy && config.GetClipboard()
+}
+
+// amountLocale returns the --locale flag, or the locale setting without it
+func amountLocale(locale string) string {
+	if locale != "" {
+		return locale
+	}
+	return config.GetLocale()
+}
+
+// completeCurrency offers ISO 4217 codes for --currency
+func completeCurrency(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var codes []string
+	for _, c := range currency.All() {
+		code := strings.ToLower(c.Code)
+		if strings.HasPrefix(code, strings.ToLower(toComplete)) {
+			codes = append(codes, code+"\t"+c.Name)
+		}
+	}
+	return codes, cobra.ShellCompDirectiveNoFileComp
+}
+
+// numberFormat describes the separators used when writing an amount
+type numberFormat struct {
+	group   byte // Thousands separator
+	decimal byte // Decimal separator
+}
+
+// decimalCommaLanguages write amounts as 1.234,56
+var decimalCommaLanguages = map[string]bool{
+	"eu": true, "de": true, "es": true, "it": true, "nl": true, "pt": true,
+	"da": true, "id": true, "tr": true, "el": true, "ro": true, "hr": true,
+	"sl": true, "sr": true, "is": true,
+}
+
+// localeFormat returns the number format for a locale such as "en", "de" or "pt-BR"
+func localeFormat(locale string) numberFormat {
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	if decimalCommaLanguages[lang] {
+		return numberFormat{group: '.', decimal: ','}
+	}
+	return numberFormat{group: ',', decimal: '.'}
+}
+
+// parseAmount converts a string amount to the currency's minor units
+// (cents for USD, yen for JPY, fils for KWD). The amount is parsed as an
+// exact decimal, so "19.99" is always 1999 and never 1998.
+func parseAmount(s string, cur currency.Currency, locale string) (int64, error) {
 	s = strings.TrimSpace(s)
-	s = strings.TrimPrefix(s, "$")
+	// Only the invoice currency's own symbol is allowed, so "$100" can't
+	// slip through as 100 euros
+	if cur.Symbol != "" {
+		s = strings.TrimPrefix(s, cur.Symbol)
+	}
+
+	if strings.HasPrefix(s, "-") {
+		return 0, fmt.Errorf("amount must be greater than zero")
+	}
 
-	var dollars float64
-	_, err := fmt.Sscanf(s, "%f", &dollars)
+	exponent := cur.MinorUnits
+	value, scale, err := parseDecimal(s, localeFormat(locale))
 	if err != nil {
 		return 0, fmt.Errorf("invalid amount: %s", s)
 	}
+	if scale > exponent {
+		if exponent == 0 {
+			return 0, fmt.Errorf("%s amounts cannot have decimal places", cur.Code)
+		}
+		return 0, fmt.Errorf("%s amounts allow at most %d decimal places", cur.Code, exponent)
+	}
 
-	if dollars <= 0 {
+	unit := pow10(exponent - scale)
+	if value > math.MaxInt64/unit {
+		return 0, fmt.Errorf("invalid amount: %s", s)
+	}
+	amount := value * unit
+
+	if amount <= 0 {
 		return 0, fmt.Errorf("amount must be greater than zero")
 	}
 
-	cents := int64(dollars * 100)
-	return cents, nil
+	return amount, nil
+}
+
+// parseDecimal parses an unsigned decimal number written in format as its
+// digits and the number of decimal places, e.g. "1,234.5" is 12345 with
+// scale 1. It never goes through floating point.
+func parseDecimal(s string, format numberFormat) (value int64, scale int, err error) {
+	whole, frac, hasFrac := strings.Cut(s, string(format.decimal))
+	if whole == "" {
+		return 0, 0, fmt.Errorf("invalid number: %s", s)
+	}
+
+	whole, err = stripGroupSeparators(whole, format.group)
+	if err != nil {
+		return 0, 0, err
+	}
+	if hasFrac && !isDigits(frac) {
+		return 0, 0, fmt.Errorf("invalid number: %s", s)
+	}
+
+	value, err = strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid number: %s", s)
+	}
+	return value, len(frac), nil
+}
+
+// pow10 returns 10^n for small n
+func pow10(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
+
+// stripGroupSeparators removes thousands separators from the integer part
+// of an amount, rejecting misplaced ones like "12,34"
+func stripGroupSeparators(s string, sep byte) (string, error) {
+	groups := strings.Split(s, string(sep))
+	for i, g := range groups {
+		if !isDigits(g) {
+			return "", fmt.Errorf("invalid digits: %s", g)
+		}
+		if len(groups) > 1 && ((i == 0 && len(g) > 3) || (i > 0 && len(g) != 3)) {
+			return "", fmt.Errorf("misplaced separator in %s", s)
+		}
+	}
+	return strings.Join(groups, ""), nil
+}
+
+// isDigits reports whether s is a non-empty run of ASCII digits
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
 }
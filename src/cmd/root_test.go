@@ -2,6 +2,11 @@ package cmd
 
 import (
 	"testing"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/forrestcai35/lane/internal/currency"
+	"github.com/spf13/cobra"
 )
 
 func TestParseAmount(t *testing.T) {
@@ -71,11 +76,67 @@ func TestParseAmount(t *testing.T) {
 			expected: 0,
 			wantErr:  true,
 		},
+		{
+			name:     "float rounding",
+			input:    "0.29",
+			expected: 29,
+			wantErr:  false,
+		},
+		{
+			name:     "float rounding with dollars",
+			input:    "19.99",
+			expected: 1999,
+			wantErr:  false,
+		},
+		{
+			name:     "thousands separator",
+			input:    "1,234.56",
+			expected: 123456,
+			wantErr:  false,
+		},
+		{
+			name:     "single decimal place",
+			input:    "12.5",
+			expected: 1250,
+			wantErr:  false,
+		},
+		{
+			name:     "trailing junk",
+			input:    "100abc",
+			expected: 0,
+			wantErr:  true,
+		},
+		{
+			name:     "extra precision",
+			input:    "1.999",
+			expected: 0,
+			wantErr:  true,
+		},
+		{
+			name:     "misplaced separator",
+			input:    "12,34",
+			expected: 0,
+			wantErr:  true,
+		},
+		{
+			name:     "trailing decimal point",
+			input:    "100.",
+			expected: 0,
+			wantErr:  true,
+		},
+		{
+			name:     "overflow",
+			input:    "999999999999999999999",
+			expected: 0,
+			wantErr:  true,
+		},
 	}
 
+	usd, _ := currency.Lookup("usd")
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := parseAmount(tt.input)
+			got, err := parseAmount(tt.input, usd, "en")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("parseAmount(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
 				return
@@ -86,3 +147,121 @@ func TestParseAmount(t *testing.T) {
 		})
 	}
 }
+
+func TestParseAmountCurrencyAndLocale(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		currency string
+		locale   string
+		expected int64
+		wantErr  bool
+	}{
+		{"zero decimal currency", "2500", "jpy", "en", 2500, false},
+		{"zero decimal rejects fraction", "2500.5", "jpy", "en", 0, true},
+		{"three decimal currency", "1.25", "kwd", "en", 1250, false},
+		{"three decimal full precision", "1.255", "KWD", "en", 1255, false},
+		{"three decimal extra precision", "1.2555", "kwd", "en", 0, true},
+		{"european decimal comma", "1.234,56", "eur", "de", 123456, false},
+		{"european region locale", "0,29", "eur", "pt-BR", 29, false},
+		{"european rejects dot decimal", "1,234.56", "eur", "de", 0, true},
+		{"english rejects decimal comma", "12,50", "usd", "en", 0, true},
+		{"currency symbol", "€49,95", "eur", "de", 4995, false},
+		{"pound symbol", "£12.50", "gbp", "en", 1250, false},
+		{"prefixed dollar symbol", "A$20", "aud", "en", 2000, false},
+		{"dollar sign for euros", "$100", "eur", "en", 0, true},
+		{"euro symbol for dollars", "€100", "usd", "en", 0, true},
+		{"dollar sign without a symbol", "$100", "chf", "en", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cur, _ := currency.Lookup(tt.currency)
+			got, err := parseAmount(tt.input, cur, tt.locale)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseAmount(%q, %q, %q) error = %v, wantErr %v", tt.input, tt.currency, tt.locale, err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.expected {
+				t.Errorf("parseAmount(%q, %q, %q) = %d, want %d", tt.input, tt.currency, tt.locale, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyProject(t *testing.T) {
+	project := &config.Project{
+		Client:            "acme",
+		Email:             "ap@acme.com",
+		DescriptionPrefix: "Acme site:",
+	}
+
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("client", "", "")
+		cmd.Flags().String("email", "", "")
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatalf("ParseFlags() error = %v", err)
+		}
+		return cmd
+	}
+
+	req := api.InvoiceRequest{Description: "Sprint 4"}
+	if !applyProject(newCmd(), &req, project) {
+		t.Error("applyProject() should keep the project's email")
+	}
+	if req.ClientName != "acme" || req.ClientEmail != "ap@acme.com" || req.Description != "Acme site: Sprint 4" {
+		t.Errorf("project not applied: %+v", req)
+	}
+
+	// An explicit --client drops the project's client and email
+	req = api.InvoiceRequest{ClientName: "Globex", Description: "Audit"}
+	if applyProject(newCmd("--client", "Globex"), &req, project) {
+		t.Error("applyProject() kept an email for another client")
+	}
+	if req.ClientName != "Globex" || req.ClientEmail != "" {
+		t.Errorf("explicit client was overridden: %+v", req)
+	}
+}
+
+func TestHourlyAmount(t *testing.T) {
+	usd, _ := currency.Lookup("usd")
+	jpy, _ := currency.Lookup("jpy")
+
+	tests := []struct {
+		rate   string
+		hours  string
+		locale string
+		cur    currency.Currency
+		want   int64
+	}{
+		{"120.50", "2.5", "en", usd, 30125},
+		{"120.50", "2,5", "de", usd, 30125},
+		{"0.10", "0.3", "en", usd, 3},         // 0.1 × 0.3 is 0.03 exactly, not 0.030000000000000002
+		{"33.33", "0.5", "en", usd, 1667},     // 1666.5 cents rounds half up
+		{"99.99", "1.005", "en", usd, 10049},  // 10048.995 cents rounds up
+		{"1000", "1.25", "en", jpy, 1250},     // No minor units
+		{"1000", "0.0005", "en", jpy, 1},      // 0.5 yen rounds up
+		{"150", "1,000", "en", usd, 15000000}, // Group separators in hours
+	}
+
+	for _, tt := range tests {
+		got, err := hourlyAmount(&config.Project{Rate: tt.rate}, tt.hours, tt.cur, tt.locale)
+		if err != nil {
+			t.Errorf("hourlyAmount(%s × %s) error = %v", tt.rate, tt.hours, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("hourlyAmount(%s × %s) = %d, want %d", tt.rate, tt.hours, got, tt.want)
+		}
+	}
+
+	if _, err := hourlyAmount(nil, "2", usd, "en"); err == nil {
+		t.Error("hourlyAmount() expected error without a project")
+	}
+	for _, hours := range []string{"-1", "0", "abc", "1.2.3", ""} {
+		if _, err := hourlyAmount(&config.Project{Rate: "120"}, hours, usd, "en"); err == nil {
+			t.Errorf("hourlyAmount() expected error for hours %q", hours)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var termsCmd = &cobra.Command{
+	Use:   "terms",
+	Short: "Manage default payment terms per client",
+	Long: `Set default payment terms for clients in your address book.
+
+Terms are kept in the address book, so these commands are shorthand for
+'lane clients edit <alias> --terms <days>' and 'lane clients list'.
+
+When an invoice is created for a client with default terms and neither
+--due nor --net is given, the due date is set that many days out.`,
+}
+
+var termsSetCmd = &cobra.Command{
+	Use:     "set <alias> <days>",
+	Short:   "Set a client's default payment terms",
+	Example: `  lane terms set acme 30`,
+	Args:    cobra.ExactArgs(2),
+	RunE:    runTermsSet,
+}
+
+var termsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List clients and their payment terms",
+	Args:  cobra.NoArgs,
+	RunE:  runClientsList,
+}
+
+var termsUnsetCmd = &cobra.Command{
+	Use:   "unset <alias>",
+	Short: "Remove a client's default payment terms",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTermsUnset,
+}
+
+func init() {
+	termsCmd.AddCommand(termsSetCmd)
+	termsCmd.AddCommand(termsListCmd)
+	termsCmd.AddCommand(termsUnsetCmd)
+	rootCmd.AddCommand(termsCmd)
+}
+
+// runTermsSet is 'lane clients edit <alias> --terms <days>'
+func runTermsSet(cmd *cobra.Command, args []string) error {
+	return editClientTerms(args[0], args[1])
+}
+
+// runTermsUnset is 'lane clients edit <alias> --terms ""'
+func runTermsUnset(cmd *cobra.Command, args []string) error {
+	return editClientTerms(args[0], "")
+}
+
+// editClientTerms runs 'lane clients edit' with only --terms passed
+func editClientTerms(alias, days string) error {
+	edit := &cobra.Command{}
+	edit.Flags().StringVar(&bookTerms, "terms", "", "")
+	if err := edit.Flags().Set("terms", days); err != nil {
+		return err
+	}
+	return runClientsEdit(edit, []string{alias})
+}
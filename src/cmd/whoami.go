@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/config"
+	"github.com/forrestcai35/lane/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var whoamiCmd = &cobra.Command{
+	Use:     "whoami",
+	Aliases: []string{"status"},
+	Short:   "Show the account you're logged in as",
+	Long: `Show the Lane account and Stripe account you're logged in to, where the
+token comes from and which API it talks to.
+
+The token is checked against the API, so this exits with an error if it
+has expired or been revoked.`,
+	Args: cobra.NoArgs,
+	RunE: runWhoami,
+}
+
+func init() {
+	rootCmd.AddCommand(whoamiCmd)
+}
+
+// sessionInfo is what whoami reports besides the user
+type sessionInfo struct {
+	profile string
+	source  string
+	apiURL  string
+	session config.Credentials
+}
+
+func runWhoami(cmd *cobra.Command, args []string) error {
+	// Load the login once; the encrypted-file store prompts for it
+	creds, err := config.GetCredentials()
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+	client := api.NewClientWithCredentials(creds)
+
+	user, err := client.GetCurrentUserContext(cmd.Context())
+	if errors.Is(err, api.ErrUnauthorized) {
+		err = withExitCode(exitNotAuthenticated, errors.New("your token has expired or been revoked. Run 'lane login' to sign in again"))
+	}
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	info := sessionInfo{
+		profile: config.GetProfile(),
+		apiURL:  config.GetAPIURL(),
+		session: client.Credentials(), // Current in case the request refreshed it
+	}
+	info.source, err = describeTokenSource()
+	if err != nil {
+		fmt.Println(formatError(err))
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(ui.ResultBox.Render(formatWhoami(user, info)))
+	fmt.Println()
+
+	return nil
+}
+
+// describeTokenSource names where the token in use comes from
+func describeTokenSource() (string, error) {
+	source, err := config.GetTokenSource()
+	if err != nil {
+		return "", err
+	}
+	if source == config.TokenSourceEnv {
+		return "$" + config.EnvAuthToken, nil
+	}
+
+	store, err := config.GetCredentialStore()
+	if err != nil {
+		return "", err
+	}
+	return describeStore(store), nil
+}
+
+// formatWhoami renders the account details
+func formatWhoami(user *api.UserResponse, info sessionInfo) string {
+	var output strings.Builder
+
+	output.WriteString(ui.FormatSuccess("Logged in"))
+	output.WriteString("\n\n")
+	if user.Name != "" {
+		output.WriteString(ui.FormatLabel("Account", user.Name))
+		output.WriteString("\n")
+	}
+	output.WriteString(ui.FormatLabel("Email", user.Email))
+	output.WriteString("\n")
+
+	stripe := ui.Subtle.Render("Not connected")
+	if acct := user.StripeAccount; acct != nil {
+		mode := "test mode"
+		if acct.Livemode {
+			mode = "live mode"
+		}
+		name := acct.Name
+		if name == "" {
+			name = acct.ID
+		} else {
+			name += " (" + acct.ID + ")"
+		}
+		stripe = name + ui.Subtle.Render(", "+mode)
+	}
+	output.WriteString(ui.FormatLabel("Stripe", stripe))
+	output.WriteString("\n\n")
+
+	output.WriteString(ui.FormatLabel("Profile", info.profile))
+	output.WriteString("\n")
+	output.WriteString(ui.FormatLabel("Token", info.source))
+	output.WriteString("\n")
+	if expires := info.session.ExpiresAt; expires != nil {
+		session := "expires " + expires.Local().Format("Mon, Jan 2 15:04")
+		if info.session.CanRefresh() {
+			session += ui.Subtle.Render(", renewed automatically")
+		}
+		output.WriteString(ui.FormatLabel("Session", session))
+		output.WriteString("\n")
+	}
+	output.WriteString(ui.FormatLabel("API", info.apiURL))
+
+	return output.String()
+}
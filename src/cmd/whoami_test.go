@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/forrestcai35/lane/internal/api"
+	"github.com/forrestcai35/lane/internal/config"
+)
+
+func TestFormatWhoami(t *testing.T) {
+	info := sessionInfo{profile: "work", source: "the system keyring", apiURL: "https://lane.example.com"}
+
+	user := &api.UserResponse{
+		Name:          "Jane Doe",
+		Email:         "jane@example.com",
+		StripeAccount: &api.StripeAccount{ID: "acct_123", Name: "Doe Design", Livemode: true},
+	}
+	got := formatWhoami(user, info)
+	for _, want := range []string{"Jane Doe", "jane@example.com", "Doe Design (acct_123)", "live mode", "work", "the system keyring", "https://lane.example.com"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatWhoami() missing %q:\n%s", want, got)
+		}
+	}
+
+	if strings.Contains(got, "Session") {
+		t.Errorf("formatWhoami() shows a session for a token that doesn't expire:\n%s", got)
+	}
+
+	expires := time.Date(2026, 11, 30, 12, 0, 0, 0, time.Local)
+	info.session = config.Credentials{Token: "t", RefreshToken: "r", ExpiresAt: &expires}
+	got = formatWhoami(user, info)
+	for _, want := range []string{"expires Mon, Nov 30 12:00", "renewed automatically"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatWhoami() missing %q:\n%s", want, got)
+		}
+	}
+
+	user.StripeAccount = nil
+	if got := formatWhoami(user, info); !strings.Contains(got, "Not connected") {
+		t.Errorf("formatWhoami() without Stripe should say not connected:\n%s", got)
+	}
+}
+
+func TestRunWhoamiRevokedToken(t *testing.T) {
+	setTempHome(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{Message: "Invalid token"})
+	}))
+	defer server.Close()
+	t.Setenv(config.EnvAPIURL, server.URL)
+	t.Setenv(config.EnvAuthToken, "revoked_token")
+
+	whoamiCmd.SetContext(context.Background())
+	err := runWhoami(whoamiCmd, nil)
+	if got := ExitCode(err); got != exitNotAuthenticated {
+		t.Errorf("runWhoami() error = %v (exit %d), want exit %d", err, got, exitNotAuthenticated)
+	}
+}
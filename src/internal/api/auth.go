@@ -0,0 +1,159 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/forrestcai35/lane/internal/config"
+)
+
+// refreshMargin is how long before expiry a token is refreshed
+const refreshMargin = time.Minute
+
+// TokenResponse is a token issued by a login or a refresh
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token,omitempty"` // Present when the token can be renewed
+	ExpiresIn    int    `json:"expires_in,omitempty"`    // Token lifetime in seconds, 0 if it doesn't expire
+}
+
+// Credentials converts the response into credentials to store, working out
+// the expiry time from now
+func (t TokenResponse) Credentials(now time.Time) config.Credentials {
+	creds := config.Credentials{Token: t.Token, RefreshToken: t.RefreshToken}
+	if t.ExpiresIn > 0 {
+		expires := now.Add(time.Duration(t.ExpiresIn) * time.Second).UTC()
+		creds.ExpiresAt = &expires
+	}
+	return creds
+}
+
+// needsRefresh reports whether the token is about to expire and can be
+// refreshed
+func (c *Client) needsRefresh() bool {
+	return c.refreshToken != "" && c.expiresAt != nil && time.Until(*c.expiresAt) < refreshMargin
+}
+
+// refresh trades the refresh token for a new token and saves it
+func (c *Client) refresh(ctx context.Context) error {
+	body, err := json.Marshal(map[string]string{"refresh_token": c.refreshToken})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/auth/refresh", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Lane-CLI/0.1.0")
+
+	resp, err := c.do(req, body)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusBadRequest {
+		// The refresh token itself has expired or been revoked
+		c.refreshToken = ""
+		return ErrUnauthorized
+	}
+	if resp.StatusCode != http.StatusOK {
+		return c.parseError(resp)
+	}
+
+	var token TokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if token.Token == "" {
+		return fmt.Errorf("failed to refresh session: no token received")
+	}
+
+	creds := token.Credentials(time.Now())
+	if creds.RefreshToken == "" {
+		creds.RefreshToken = c.refreshToken // Not rotated
+	}
+
+	c.token = creds.Token
+	c.refreshToken = creds.RefreshToken
+	c.expiresAt = creds.ExpiresAt
+
+	if c.onRefresh != nil {
+		if err := c.onRefresh(creds); err != nil {
+			return fmt.Errorf("failed to save refreshed session: %w", err)
+		}
+	}
+	return nil
+}
+
+// RevokeToken invalidates the token, and its refresh token if any, on the
+// server. An expired token is refreshed first, so a refresh token that can
+// still mint tokens is never left behind. A rejected token counts as
+// revoked only when there is no refresh token, or the refresh token is
+// rejected too.
+func (c *Client) RevokeToken() error {
+	return c.RevokeTokenContext(context.Background())
+}
+
+// RevokeTokenContext is RevokeToken with a context that cancels the request
+func (c *Client) RevokeTokenContext(ctx context.Context) error {
+	// A rejected refresh token is dropped by refresh, leaving only the
+	// token itself to revoke
+	refreshed := false
+	if c.needsRefresh() {
+		if err := c.refresh(ctx); err != nil && !errors.Is(err, ErrUnauthorized) {
+			return err
+		}
+		refreshed = true
+	}
+
+	resp, err := c.sendRevoke(ctx)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && c.refreshToken != "" && !refreshed {
+		resp.Body.Close()
+		if err := c.refresh(ctx); errors.Is(err, ErrUnauthorized) {
+			return nil // Neither token is accepted any more
+		} else if err != nil {
+			return err
+		}
+		if resp, err = c.sendRevoke(ctx); err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusUnauthorized:
+		if c.refreshToken == "" {
+			return nil
+		}
+		return fmt.Errorf("the server rejected the token, so its refresh token was not revoked")
+	default:
+		return c.parseError(resp)
+	}
+}
+
+// sendRevoke asks the server to revoke the token, and the current refresh
+// token if there is one
+func (c *Client) sendRevoke(ctx context.Context) (*http.Response, error) {
+	var body []byte
+	if c.refreshToken != "" {
+		var err error
+		body, err = json.Marshal(map[string]string{"refresh_token": c.refreshToken})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+	return c.send(ctx, "POST", "/api/auth/revoke", body, "")
+}
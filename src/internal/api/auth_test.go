@@ -0,0 +1,241 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/forrestcai35/lane/internal/config"
+)
+
+// fakeAuthAPI accepts only validToken on /api/v1/me and issues it from
+// /api/auth/refresh in exchange for "refresh_1"
+func fakeAuthAPI(t *testing.T, validToken string) (*httptest.Server, *int) {
+	t.Helper()
+
+	refreshes := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/me", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+validToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(UserResponse{ID: "user_123"})
+	})
+	mux.HandleFunc("/api/auth/refresh", func(w http.ResponseWriter, r *http.Request) {
+		refreshes++
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["refresh_token"] != "refresh_1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(TokenResponse{Token: validToken, RefreshToken: "refresh_2", ExpiresIn: 3600})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &refreshes
+}
+
+func TestRefreshBeforeExpiry(t *testing.T) {
+	server, refreshes := fakeAuthAPI(t, "fresh_token")
+
+	var saved config.Credentials
+	expired := time.Now().Add(-time.Minute)
+	client := &Client{
+		baseURL:      server.URL,
+		token:        "stale_token",
+		httpClient:   http.DefaultClient,
+		refreshToken: "refresh_1",
+		expiresAt:    &expired,
+		onRefresh:    func(c config.Credentials) error { saved = c; return nil },
+	}
+
+	if _, err := client.GetCurrentUser(); err != nil {
+		t.Fatalf("GetCurrentUser() error = %v", err)
+	}
+	if *refreshes != 1 {
+		t.Errorf("refreshed %d times, want 1", *refreshes)
+	}
+	if saved.Token != "fresh_token" || saved.RefreshToken != "refresh_2" || saved.ExpiresAt == nil {
+		t.Errorf("saved credentials = %+v", saved)
+	}
+	if time.Until(*saved.ExpiresAt) < 59*time.Minute {
+		t.Errorf("ExpiresAt = %v, want about an hour from now", saved.ExpiresAt)
+	}
+}
+
+func TestRefreshOnUnauthorized(t *testing.T) {
+	server, refreshes := fakeAuthAPI(t, "fresh_token")
+
+	client := &Client{
+		baseURL:      server.URL,
+		token:        "revoked_token",
+		httpClient:   http.DefaultClient,
+		refreshToken: "refresh_1",
+	}
+
+	if _, err := client.GetCurrentUser(); err != nil {
+		t.Fatalf("GetCurrentUser() error = %v", err)
+	}
+	if *refreshes != 1 || client.token != "fresh_token" {
+		t.Errorf("refreshes = %d, token = %q", *refreshes, client.token)
+	}
+}
+
+func TestRefreshFailure(t *testing.T) {
+	server, _ := fakeAuthAPI(t, "fresh_token")
+
+	client := &Client{
+		baseURL:      server.URL,
+		token:        "revoked_token",
+		httpClient:   http.DefaultClient,
+		refreshToken: "refresh_revoked",
+	}
+
+	if _, err := client.GetCurrentUser(); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("GetCurrentUser() error = %v, want ErrUnauthorized", err)
+	}
+
+	// Without a refresh token, a 401 is reported without trying to refresh
+	client = &Client{baseURL: server.URL, token: "revoked_token", httpClient: http.DefaultClient}
+	if _, err := client.GetCurrentUser(); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("GetCurrentUser() error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	var gotAuth string
+	var gotBody map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/auth/revoke" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		gotAuth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &Client{baseURL: server.URL, token: "test-token", httpClient: http.DefaultClient, refreshToken: "refresh_1"}
+	if err := client.RevokeToken(); err != nil {
+		t.Fatalf("RevokeToken() error = %v", err)
+	}
+	if gotAuth != "Bearer test-token" || gotBody["refresh_token"] != "refresh_1" {
+		t.Errorf("revoke request: auth %q, body %v", gotAuth, gotBody)
+	}
+}
+
+// fakeRevokeAPI accepts revokes authorized with validToken and refreshes
+// "refresh_1" into it, recording the refresh token each revoke names
+func fakeRevokeAPI(t *testing.T, validToken string, revoked *[]string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/auth/revoke", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+validToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		*revoked = append(*revoked, body["refresh_token"])
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/api/auth/refresh", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["refresh_token"] != "refresh_1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(TokenResponse{Token: validToken, RefreshToken: "refresh_2", ExpiresIn: 3600})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRevokeTokenRefreshesExpiredToken(t *testing.T) {
+	expired := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+	}{
+		{"known expiry", &expired},
+		{"rejected without expiry", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var revoked []string
+			server := fakeRevokeAPI(t, "fresh_token", &revoked)
+			client := &Client{
+				baseURL:      server.URL,
+				token:        "stale_token",
+				httpClient:   http.DefaultClient,
+				refreshToken: "refresh_1",
+				expiresAt:    tt.expiresAt,
+			}
+
+			if err := client.RevokeToken(); err != nil {
+				t.Fatalf("RevokeToken() error = %v", err)
+			}
+			if len(revoked) != 1 || revoked[0] != "refresh_2" {
+				t.Errorf("revoked refresh tokens %v, want the rotated refresh_2", revoked)
+			}
+		})
+	}
+}
+
+func TestRevokeTokenRejected(t *testing.T) {
+	var revoked []string
+	server := fakeRevokeAPI(t, "fresh_token", &revoked)
+
+	// Without a refresh token, a rejected token is already unusable
+	client := &Client{baseURL: server.URL, token: "stale_token", httpClient: http.DefaultClient}
+	if err := client.RevokeToken(); err != nil {
+		t.Errorf("RevokeToken() without a refresh token error = %v", err)
+	}
+
+	// Both tokens rejected: nothing is left to revoke
+	client = &Client{baseURL: server.URL, token: "stale_token", httpClient: http.DefaultClient, refreshToken: "refresh_revoked"}
+	if err := client.RevokeToken(); err != nil {
+		t.Errorf("RevokeToken() with a rejected refresh token error = %v", err)
+	}
+
+	// The refreshed token is rejected too: the refresh token may be live
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/auth/revoke", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	mux.HandleFunc("/api/auth/refresh", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(TokenResponse{Token: "fresh_token", RefreshToken: "refresh_2"})
+	})
+	rejecting := httptest.NewServer(mux)
+	defer rejecting.Close()
+
+	client = &Client{baseURL: rejecting.URL, token: "stale_token", httpClient: http.DefaultClient, refreshToken: "refresh_1"}
+	if err := client.RevokeToken(); err == nil {
+		t.Error("RevokeToken() expected error when the refresh token couldn't be revoked")
+	}
+}
+
+func TestTokenResponseCredentials(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	creds := TokenResponse{Token: "t", RefreshToken: "r", ExpiresIn: 3600}.Credentials(now)
+	if creds.ExpiresAt == nil || !creds.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("ExpiresAt = %v, want %v", creds.ExpiresAt, now.Add(time.Hour))
+	}
+
+	creds = TokenResponse{Token: "t"}.Credentials(now)
+	if creds.ExpiresAt != nil || creds.CanRefresh() {
+		t.Errorf("a token without metadata = %+v, want a plain token", creds)
+	}
+}
@@ -2,10 +2,15 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/forrestcai35/lane/internal/config"
@@ -16,63 +21,166 @@ type Client struct {
 	baseURL    string
 	token      string
 	httpClient *http.Client
+
+	// Set when the login can be refreshed without the user
+	refreshToken string
+	expiresAt    *time.Time
+	onRefresh    func(config.Credentials) error // Persists refreshed credentials
 }
 
 // NewClient creates a new Lane API client
 func NewClient() (*Client, error) {
-	token, err := config.GetAuthToken()
+	creds, err := config.GetCredentials()
 	if err != nil {
 		return nil, err
 	}
+	return NewClientWithCredentials(creds), nil
+}
 
+// NewClientWithCredentials creates a Lane API client for credentials that
+// were already loaded. Refreshed credentials are saved to the store.
+func NewClientWithCredentials(creds config.Credentials) *Client {
+	c := NewClientWithToken(creds.Token)
+	if creds.CanRefresh() {
+		c.refreshToken = creds.RefreshToken
+		c.expiresAt = creds.ExpiresAt
+		c.onRefresh = config.SaveCredentials
+	}
+	return c
+}
+
+// Credentials returns the client's current login, which differs from the
+// one it was created with once the token has been refreshed
+func (c *Client) Credentials() config.Credentials {
+	return config.Credentials{
+		Token:        c.token,
+		RefreshToken: c.refreshToken,
+		ExpiresAt:    c.expiresAt,
+	}
+}
+
+// NewClientWithToken creates a Lane API client that uses the given token
+// instead of the stored one
+func NewClientWithToken(token string) *Client {
 	return &Client{
 		baseURL: config.GetAPIURL(),
 		token:   token,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: config.GetTimeout(),
 		},
-	}, nil
+	}
 }
 
 // InvoiceRequest is the request body for creating an invoice
 type InvoiceRequest struct {
-	Amount      int64  `json:"amount"`       // Amount in cents
-	Currency    string `json:"currency"`     // e.g., "usd"
-	ClientName  string `json:"client_name"`  // Client's name
-	ClientEmail string `json:"client_email"` // Client's email (for sending)
-	Description string `json:"description"`  // Invoice description
-	SendEmail   bool   `json:"send_email"`   // Whether to send email
+	Amount      int64      `json:"amount"`               // Total amount in cents
+	Currency    string     `json:"currency"`             // e.g., "usd"
+	ClientName  string     `json:"client_name"`          // Client's name
+	ClientEmail string     `json:"client_email"`         // Client's email (for sending)
+	Description string     `json:"description"`          // Invoice description
+	SendEmail   bool       `json:"send_email"`           // Whether to send email
+	LineItems   []LineItem `json:"line_items,omitempty"` // Itemized lines (Amount is their total)
+	Draft       bool       `json:"draft,omitempty"`      // Leave unfinalized for review
+	DueDate     *time.Time `json:"due_date,omitempty"`   // End of the due day in the sender's time zone
+
+	// Extra client details, usually filled in from the address book
+	CustomerID    string   `json:"customer_id,omitempty"`    // Existing Stripe customer, used instead of ClientName
+	CCEmails      []string `json:"cc_emails,omitempty"`      // Additional billing emails
+	ClientAddress string   `json:"client_address,omitempty"` // Billing address
+	ClientTaxID   string   `json:"client_tax_id,omitempty"`  // VAT number or other tax ID
+}
+
+// LineItem is a single line on an itemized invoice
+type LineItem struct {
+	Description string  `json:"description"`        // Line description
+	Quantity    int64   `json:"quantity"`           // Number of units
+	UnitAmount  int64   `json:"unit_amount"`        // Price per unit in cents
+	TaxRate     Percent `json:"tax_rate,omitempty"` // Tax rate, e.g. 20% is 20 * PercentScale
+
+	// Set by the API on returned invoices
+	Amount    int64 `json:"amount,omitempty"`     // Line amount before tax in cents
+	TaxAmount int64 `json:"tax_amount,omitempty"` // Tax on the line in cents
+}
+
+// Subtotal returns the line amount before tax
+func (l LineItem) Subtotal() int64 {
+	return l.Quantity * l.UnitAmount
+}
+
+// PercentScale is the number of Percent units in one percent, so rates
+// keep up to four decimal places exactly
+const PercentScale = 10000
+
+// Percent is a percentage in ten-thousandths of a percent. It is sent as
+// a plain decimal number, e.g. 8.5 for 8.5%.
+type Percent int64
+
+// String formats the percentage without trailing zeros, e.g. "8.5"
+func (p Percent) String() string {
+	s := strconv.FormatInt(int64(p)/PercentScale, 10)
+	if frac := int64(p) % PercentScale; frac != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%04d", frac), "0")
+	}
+	return s
+}
+
+// MarshalJSON writes the percentage as an exact decimal number
+func (p Percent) MarshalJSON() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalJSON reads a decimal number with at most four decimal places
+func (p *Percent) UnmarshalJSON(data []byte) error {
+	whole, frac, _ := strings.Cut(string(data), ".")
+	if len(frac) > 4 {
+		return fmt.Errorf("invalid percentage %s: too many decimal places", data)
+	}
+	frac += strings.Repeat("0", 4-len(frac))
+	n, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil || n < 0 {
+		return fmt.Errorf("invalid percentage %s", data)
+	}
+	*p = Percent(n)
+	return nil
 }
 
-// InvoiceResponse is the response from creating an invoice
+// InvoiceResponse is the response from creating or sending an invoice
 type InvoiceResponse struct {
-	ID          string `json:"id"`           // Invoice ID
-	PaymentLink string `json:"payment_link"` // Stripe payment link
-	PDFUrl      string `json:"pdf_url"`      // URL to download PDF
-	EmailSent   bool   `json:"email_sent"`   // Whether email was sent
+	ID          string `json:"id"`               // Invoice ID
+	Status      string `json:"status,omitempty"` // e.g., "draft" or "open"
+	PaymentLink string `json:"payment_link"`     // Stripe payment link (empty for drafts)
+	PDFUrl      string `json:"pdf_url"`          // URL to download PDF
+	EmailSent   bool   `json:"email_sent"`       // Whether email was sent
 }
 
 // UserResponse is the response from the /me endpoint
 type UserResponse struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	Email         string         `json:"email"`
+	StripeAccount *StripeAccount `json:"stripe_account,omitempty"` // nil until Stripe is connected
 }
 
-// ErrorResponse is returned on API errors
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
+// StripeAccount is the Stripe account invoices are created in
+type StripeAccount struct {
+	ID       string `json:"id"`       // e.g., "acct_1Nv0Xa2eZvKYlo2C"
+	Name     string `json:"name"`     // Business name
+	Livemode bool   `json:"livemode"` // False for test mode
 }
 
 // CreateInvoice creates a new invoice via the Lane API
 func (c *Client) CreateInvoice(req InvoiceRequest) (*InvoiceResponse, error) {
+	return c.CreateInvoiceContext(context.Background(), req)
+}
+
+// CreateInvoiceContext is CreateInvoice with a context that cancels the request
+func (c *Client) CreateInvoiceContext(ctx context.Context, req InvoiceRequest) (*InvoiceResponse, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	resp, err := c.request("POST", "/api/v1/invoices", body)
+	resp, err := c.request(ctx, "POST", "/api/v1/invoices", body)
 	if err != nil {
 		return nil, err
 	}
@@ -92,7 +200,12 @@ func (c *Client) CreateInvoice(req InvoiceRequest) (*InvoiceResponse, error) {
 
 // GetCurrentUser returns the authenticated user's info
 func (c *Client) GetCurrentUser() (*UserResponse, error) {
-	resp, err := c.request("GET", "/api/v1/me", nil)
+	return c.GetCurrentUserContext(context.Background())
+}
+
+// GetCurrentUserContext is GetCurrentUser with a context that cancels the request
+func (c *Client) GetCurrentUserContext(ctx context.Context) (*UserResponse, error) {
+	resp, err := c.request(ctx, "GET", "/api/v1/me", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -110,8 +223,79 @@ func (c *Client) GetCurrentUser() (*UserResponse, error) {
 	return &user, nil
 }
 
-// request makes an authenticated HTTP request to the Lane API
-func (c *Client) request(method, path string, body []byte) (*http.Response, error) {
+// request makes an authenticated HTTP request to the Lane API. An expired
+// token is refreshed first, and a request rejected with 401 is retried once
+// after refreshing, when the login has a refresh token. Mutating requests
+// carry an idempotency key that stays the same across every retry, so the
+// server never applies them twice.
+func (c *Client) request(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	var key string
+	if mutating(method) {
+		var err error
+		if key, err = newIdempotencyKey(); err != nil {
+			return nil, err
+		}
+	}
+
+	refreshed := false
+	if c.needsRefresh() {
+		if err := c.refresh(ctx); err != nil {
+			return nil, err
+		}
+		refreshed = true
+	}
+
+	resp, err := c.sendWithRetry(ctx, method, path, body, key)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || refreshed || c.refreshToken == "" {
+		return resp, err
+	}
+
+	resp.Body.Close()
+	if err := c.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return c.sendWithRetry(ctx, method, path, body, key)
+}
+
+// sendWithRetry sends a request, retrying connection errors, 429 and 5xx
+// responses with exponential backoff. A Retry-After header from the server
+// replaces the backoff; if it asks for too long a wait, the response is
+// returned instead. Cancelling ctx stops the request and any wait.
+func (c *Client) sendWithRetry(ctx context.Context, method, path string, body []byte, key string) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, method, path, body, key)
+		if attempt == maxAttempts {
+			return resp, err
+		}
+
+		var urlErr *url.Error
+		wait := backoff(attempt)
+		switch {
+		case err != nil && errors.As(err, &urlErr) && ctx.Err() == nil:
+			// Connection error, timeout and the like
+		case err != nil:
+			return nil, err
+		case retryable(resp.StatusCode):
+			if after, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if after > maxRetryAfter {
+					return resp, nil
+				}
+				wait = after
+			}
+			resp.Body.Close()
+		default:
+			return resp, nil
+		}
+
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send makes a single authenticated HTTP request. key is sent as the
+// Idempotency-Key header when set.
+func (c *Client) send(ctx context.Context, method, path string, body []byte, key string) (*http.Response, error) {
 	url := c.baseURL + path
 
 	var bodyReader io.Reader
@@ -119,7 +303,7 @@ func (c *Client) request(method, path string, body []byte) (*http.Response, erro
 		bodyReader = bytes.NewReader(body)
 	}
 
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -127,23 +311,14 @@ func (c *Client) request(method, path string, body []byte) (*http.Response, erro
 	req.Header.Set("Authorization", "Bearer "+c.token)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "Lane-CLI/0.1.0")
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req, body)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
 	return resp, nil
 }
-
-// parseError extracts an error message from an API response
-func (c *Client) parseError(resp *http.Response) error {
-	body, _ := io.ReadAll(resp.Body)
-
-	var errResp ErrorResponse
-	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Message != "" {
-		return fmt.Errorf("%s", errResp.Message)
-	}
-
-	return fmt.Errorf("API error: %s", resp.Status)
-}
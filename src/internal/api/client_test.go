@@ -2,10 +2,13 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
-	"os"
+	"strings"
 	"testing"
+
+	"github.com/forrestcai35/lane/internal/config"
 )
 
 func TestCreateInvoice(t *testing.T) {
@@ -139,23 +142,81 @@ func TestGetCurrentUser(t *testing.T) {
 	}
 }
 
+func TestGetCurrentUserStripeAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"user_123","name":"Test User","email":"test@example.com",
+			"stripe_account":{"id":"acct_123","name":"Test Co","livemode":true}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{baseURL: server.URL, token: "test-token", httpClient: http.DefaultClient}
+
+	user, err := client.GetCurrentUser()
+	if err != nil {
+		t.Fatalf("GetCurrentUser() error = %v", err)
+	}
+	if user.StripeAccount == nil || user.StripeAccount.ID != "acct_123" || !user.StripeAccount.Livemode {
+		t.Errorf("unexpected Stripe account: %+v", user.StripeAccount)
+	}
+}
+
+func TestGetCurrentUserUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "unauthorized", Message: "Token has been revoked"})
+	}))
+	defer server.Close()
+
+	client := &Client{baseURL: server.URL, token: "revoked-token", httpClient: http.DefaultClient}
+
+	_, err := client.GetCurrentUser()
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("GetCurrentUser() error = %v, want ErrUnauthorized", err)
+	}
+	if !strings.Contains(err.Error(), "lane login") {
+		t.Errorf("error %q should tell the user to log in again", err.Error())
+	}
+}
+
 func TestNewClientRequiresAuth(t *testing.T) {
-	// Ensure no token is available
-	originalToken := os.Getenv("LANE_TOKEN")
-	os.Unsetenv("LANE_TOKEN")
-	defer os.Setenv("LANE_TOKEN", originalToken)
-
-	// Use a temp home with no token file
-	originalHome := os.Getenv("HOME")
-	tmpDir, _ := os.MkdirTemp("", "lane-test-*")
-	os.Setenv("HOME", tmpDir)
-	defer func() {
-		os.Setenv("HOME", originalHome)
-		os.RemoveAll(tmpDir)
-	}()
+	// Ensure no token is available: no env token and a temp home with no
+	// token file
+	t.Setenv(config.EnvAuthToken, "")
+	t.Setenv(config.EnvCredentialStore, config.StoreFile)
+	t.Setenv("HOME", t.TempDir())
 
 	_, err := NewClient()
 	if err == nil {
 		t.Error("NewClient() should fail when not authenticated")
 	}
 }
+
+func TestPercentJSON(t *testing.T) {
+	tests := []struct {
+		rate Percent
+		json string
+	}{
+		{20 * PercentScale, "20"},
+		{85000, "8.5"},
+		{10050, "1.005"},
+		{1, "0.0001"},
+	}
+
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.rate)
+		if err != nil || string(data) != tt.json {
+			t.Errorf("Marshal(%d) = %s, %v, want %s", tt.rate, data, err, tt.json)
+		}
+		var got Percent
+		if err := json.Unmarshal([]byte(tt.json), &got); err != nil || got != tt.rate {
+			t.Errorf("Unmarshal(%s) = %d, %v, want %d", tt.json, got, err, tt.rate)
+		}
+	}
+
+	var p Percent
+	for _, bad := range []string{"1.00001", "-5", `"20"`} {
+		if err := json.Unmarshal([]byte(bad), &p); err == nil {
+			t.Errorf("Unmarshal(%s) expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Customer is a Stripe customer on the connected account
+type Customer struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Currency string `json:"currency,omitempty"`
+	Address  string `json:"address,omitempty"`
+	TaxID    string `json:"tax_id,omitempty"`
+}
+
+// CustomerList is one page of customers
+type CustomerList struct {
+	Data       []Customer `json:"data"`
+	HasMore    bool       `json:"has_more"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// ListCustomers returns a page of customers. Pass the previous page's
+// NextCursor to continue; limit 0 uses the server default.
+func (c *Client) ListCustomers(limit int, cursor string) (*CustomerList, error) {
+	return c.ListCustomersContext(context.Background(), limit, cursor)
+}
+
+// ListCustomersContext is ListCustomers with a context that cancels the request
+func (c *Client) ListCustomersContext(ctx context.Context, limit int, cursor string) (*CustomerList, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	path := "/api/v1/customers"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := c.request(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var list CustomerList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &list, nil
+}
+
+// UpsertCustomer creates a customer, or updates it when ID is set
+func (c *Client) UpsertCustomer(customer Customer) (*Customer, error) {
+	return c.UpsertCustomerContext(context.Background(), customer)
+}
+
+// UpsertCustomerContext is UpsertCustomer with a context that cancels the request
+func (c *Client) UpsertCustomerContext(ctx context.Context, customer Customer) (*Customer, error) {
+	body, err := json.Marshal(customer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	method, path := "POST", "/api/v1/customers"
+	if customer.ID != "" {
+		method, path = "PUT", "/api/v1/customers/"+url.PathEscape(customer.ID)
+	}
+
+	resp, err := c.request(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, c.parseError(resp)
+	}
+
+	var result Customer
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &result, nil
+}
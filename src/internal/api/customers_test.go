@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListCustomers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v1/customers" {
+			t.Errorf("expected /api/v1/customers, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("cursor") != "cus_1" {
+			t.Errorf("expected cursor=cus_1, got %q", r.URL.Query().Get("cursor"))
+		}
+
+		json.NewEncoder(w).Encode(CustomerList{
+			Data: []Customer{{ID: "cus_2", Name: "Globex", Email: "ap@globex.com"}},
+		})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: http.DefaultClient,
+	}
+
+	list, err := client.ListCustomers(0, "cus_1")
+	if err != nil {
+		t.Fatalf("ListCustomers() error = %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].ID != "cus_2" || list.HasMore {
+		t.Errorf("unexpected list: %+v", list)
+	}
+}
+
+func TestUpsertCustomer(t *testing.T) {
+	tests := []struct {
+		name       string
+		customer   Customer
+		wantMethod string
+		wantPath   string
+	}{
+		{"create", Customer{Name: "Acme Corp", Email: "ap@acme.com"}, "POST", "/api/v1/customers"},
+		{"update", Customer{ID: "cus_1", Name: "Acme Corp", Email: "ap@acme.com"}, "PUT", "/api/v1/customers/cus_1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tt.wantMethod {
+					t.Errorf("expected %s, got %s", tt.wantMethod, r.Method)
+				}
+				if r.URL.Path != tt.wantPath {
+					t.Errorf("expected %s, got %s", tt.wantPath, r.URL.Path)
+				}
+
+				var customer Customer
+				json.NewDecoder(r.Body).Decode(&customer)
+				customer.ID = "cus_1"
+				json.NewEncoder(w).Encode(customer)
+			}))
+			defer server.Close()
+
+			client := &Client{
+				baseURL:    server.URL,
+				token:      "test-token",
+				httpClient: http.DefaultClient,
+			}
+
+			got, err := client.UpsertCustomer(tt.customer)
+			if err != nil {
+				t.Fatalf("UpsertCustomer() error = %v", err)
+			}
+			if got.ID != "cus_1" || got.Email != "ap@acme.com" {
+				t.Errorf("unexpected customer: %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redacted replaces secrets and personal data in traces
+const redacted = "[redacted]"
+
+var (
+	debugMu  sync.Mutex
+	debugOut io.Writer // Receives request traces; nil turns them off
+)
+
+// sensitiveHeaders are never written to a trace
+var sensitiveHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+// emailPattern matches email addresses, including URL-encoded ones
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+(@|%40)[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// SetDebugOutput traces every API request and response to w, with tokens
+// and email addresses redacted. A nil w turns tracing off.
+func SetDebugOutput(w io.Writer) {
+	debugMu.Lock()
+	defer debugMu.Unlock()
+	debugOut = w
+}
+
+func debugWriter() io.Writer {
+	debugMu.Lock()
+	defer debugMu.Unlock()
+	return debugOut
+}
+
+// do sends req, tracing it when debugging is on. body is the request body
+// already attached to req.
+func (c *Client) do(req *http.Request, body []byte) (*http.Response, error) {
+	w := debugWriter()
+	if w == nil {
+		return c.httpClient.Do(req)
+	}
+
+	var trace strings.Builder
+	start := time.Now()
+	fmt.Fprintf(&trace, "%s --> %s %s\n", start.Format(time.RFC3339), req.Method, redactText(req.URL.String()))
+	writeHeaders(&trace, req.Header)
+	writeBody(&trace, body)
+
+	resp, err := c.httpClient.Do(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(&trace, "%s <-- error after %s: %s\n", time.Now().Format(time.RFC3339), elapsed, redactText(err.Error()))
+	} else {
+		data, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), errReader{readErr}))
+
+		fmt.Fprintf(&trace, "%s <-- %s (%s)\n", time.Now().Format(time.RFC3339), resp.Status, elapsed)
+		writeHeaders(&trace, resp.Header)
+		writeBody(&trace, data)
+	}
+
+	debugMu.Lock()
+	io.WriteString(w, trace.String())
+	debugMu.Unlock()
+	return resp, err
+}
+
+// errReader returns err once the buffered body is used up, so a body that
+// failed mid-read still fails for the caller
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
+
+// writeHeaders writes headers in a stable order, hiding credentials
+func writeHeaders(w io.Writer, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		value := strings.Join(header[k], ", ")
+		if sensitiveHeaders[http.CanonicalHeaderKey(k)] {
+			value = redacted
+		}
+		fmt.Fprintf(w, "    %s: %s\n", k, redactText(value))
+	}
+}
+
+// writeBody writes a JSON body with secrets redacted. Other bodies are
+// summarized by size, since they may hold anything.
+func writeBody(w io.Writer, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		fmt.Fprintf(w, "    (%d bytes, not JSON)\n", len(body))
+		return
+	}
+	data, err := json.Marshal(redactJSON(v, false))
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "    %s\n", data)
+}
+
+// redactJSON hides the string values of sensitive keys and any email
+// addresses in a decoded JSON value. Flags like send_email stay readable.
+func redactJSON(v any, sensitive bool) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			v[k] = redactJSON(val, sensitive || sensitiveKey(k))
+		}
+		return v
+	case []any:
+		for i, val := range v {
+			v[i] = redactJSON(val, sensitive)
+		}
+		return v
+	case string:
+		if sensitive {
+			return redacted
+		}
+		return redactText(v)
+	}
+	return v
+}
+
+// sensitiveKey reports whether a JSON key holds a credential or an email
+func sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range []string{"token", "secret", "password", "passphrase", "code_verifier", "email"} {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactText hides email addresses in free text
+func redactText(s string) string {
+	return emailPattern.ReplaceAllString(s, redacted)
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req_123")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(InvoiceResponse{
+			ID:          "inv_123",
+			PaymentLink: "https://pay.stripe.com/inv_123",
+		})
+	}))
+	defer server.Close()
+
+	var trace bytes.Buffer
+	SetDebugOutput(&trace)
+	defer SetDebugOutput(nil)
+
+	client := &Client{
+		baseURL:    server.URL,
+		token:      "secret-token",
+		httpClient: http.DefaultClient,
+	}
+
+	resp, err := client.CreateInvoice(InvoiceRequest{
+		Amount:      10000,
+		Currency:    "usd",
+		ClientName:  "Acme Corp",
+		ClientEmail: "billing@acme.com",
+		CCEmails:    []string{"ceo@acme.com"},
+		Description: "Consulting for jane@acme.com",
+		SendEmail:   true,
+	})
+	if err != nil {
+		t.Fatalf("CreateInvoice() error = %v", err)
+	}
+	if resp.ID != "inv_123" {
+		t.Errorf("CreateInvoice() ID = %q, want inv_123 after tracing the body", resp.ID)
+	}
+
+	got := trace.String()
+	for _, want := range []string{
+		"--> POST " + server.URL + "/api/v1/invoices",
+		"Authorization: [redacted]",
+		"Idempotency-Key: ",
+		`"amount":10000`,
+		`"client_email":"[redacted]"`,
+		`"cc_emails":["[redacted]"]`,
+		`"description":"Consulting for [redacted]"`,
+		`"send_email":true`,
+		"<-- 201 Created (",
+		"X-Request-Id: req_123",
+		`"id":"inv_123"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("trace is missing %q:\n%s", want, got)
+		}
+	}
+	for _, secret := range []string{"secret-token", "acme.com"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("trace leaks %q:\n%s", secret, got)
+		}
+	}
+}
+
+func TestDebugTraceRefresh(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(TokenResponse{Token: "new-token", RefreshToken: "new-refresh"})
+	}))
+	defer server.Close()
+
+	var trace bytes.Buffer
+	SetDebugOutput(&trace)
+	defer SetDebugOutput(nil)
+
+	client := &Client{
+		baseURL:      server.URL,
+		httpClient:   http.DefaultClient,
+		refreshToken: "old-refresh",
+	}
+	if err := client.refresh(context.Background()); err != nil {
+		t.Fatalf("refresh() error = %v", err)
+	}
+
+	got := trace.String()
+	for _, secret := range []string{"old-refresh", "new-token", "new-refresh"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("trace leaks %q:\n%s", secret, got)
+		}
+	}
+	if !strings.Contains(got, `"refresh_token":"[redacted]"`) {
+		t.Errorf("trace is missing the redacted refresh token:\n%s", got)
+	}
+}
+
+func TestRedactText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"no address here", "no address here"},
+		{"mail tim@apple.com today", "mail [redacted] today"},
+		{"/api/v1/customers?email=tim%40apple.com", "/api/v1/customers?email=[redacted]"},
+	}
+
+	for _, tt := range tests {
+		if got := redactText(tt.in); got != tt.want {
+			t.Errorf("redactText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// Sentinel errors, matched with errors.Is against an *Error
+var (
+	// ErrUnauthorized is returned when the API rejects the auth token and it
+	// can't be refreshed
+	ErrUnauthorized = errors.New("session expired. Run 'lane login' to sign in again")
+
+	// ErrNotFound is returned when an invoice, customer or other resource
+	// doesn't exist
+	ErrNotFound = errors.New("not found")
+
+	// ErrRateLimited is returned when requests are still throttled after
+	// retrying
+	ErrRateLimited = errors.New("too many requests. Wait a minute and try again")
+
+	// ErrInvalidRequest is returned when the API rejects the request's
+	// values; the *Error lists the fields at fault
+	ErrInvalidRequest = errors.New("invalid request")
+
+	// ErrStripe is returned when Stripe declines the operation, e.g. a
+	// customer's card or an account that isn't fully set up
+	ErrStripe = errors.New("declined by Stripe")
+)
+
+// ErrorResponse is returned on API errors
+type ErrorResponse struct {
+	Error     string       `json:"error"` // Machine-readable code, e.g. "invalid_request"
+	Message   string       `json:"message"`
+	RequestID string       `json:"request_id,omitempty"`
+	Param     string       `json:"param,omitempty"`  // Request field that caused the error
+	Errors    []FieldError `json:"errors,omitempty"` // One entry per invalid field
+}
+
+// FieldError explains why a single request field was rejected
+type FieldError struct {
+	Param   string `json:"param"` // e.g. "client_email" or "line_items[1].unit_amount"
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error is an error response from the Lane API
+type Error struct {
+	StatusCode int
+	Code       string // Machine-readable code, e.g. "invalid_request"
+	Message    string
+	RequestID  string // Quote this when contacting support
+	Param      string
+	Fields     []FieldError
+}
+
+// Error returns the API's message
+func (e *Error) Error() string {
+	if e.StatusCode == http.StatusUnauthorized {
+		return ErrUnauthorized.Error()
+	}
+	return e.Message
+}
+
+// Is matches the sentinel errors by status and code
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrInvalidRequest:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrStripe:
+		return e.StatusCode == http.StatusPaymentRequired || e.Code == "stripe_error" || e.Code == "card_declined"
+	}
+	return false
+}
+
+// parseError reads an error response into an *Error
+func (c *Client) parseError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	var errResp ErrorResponse
+	json.Unmarshal(body, &errResp)
+
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		Code:       errResp.Error,
+		Message:    errResp.Message,
+		RequestID:  errResp.RequestID,
+		Param:      errResp.Param,
+		Fields:     errResp.Errors,
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-Request-Id")
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = defaultMessage(resp)
+	}
+	return apiErr
+}
+
+// defaultMessage describes a response that came without a message
+func defaultMessage(resp *http.Response) string {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return ErrRateLimited.Error()
+	}
+	return "API error: " + resp.Status
+}
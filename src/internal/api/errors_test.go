@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req_header")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "invalid_request",
+			Message: "The invoice has invalid fields",
+			Errors: []FieldError{
+				{Param: "client_email", Code: "invalid_email", Message: "is not a valid email address"},
+				{Param: "line_items[1].unit_amount", Message: "must be positive"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := &Client{baseURL: server.URL, token: "test-token", httpClient: http.DefaultClient}
+	_, err := client.CreateInvoice(InvoiceRequest{Amount: 1000})
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("CreateInvoice() error = %T, want *Error", err)
+	}
+	if apiErr.StatusCode != http.StatusUnprocessableEntity || apiErr.Code != "invalid_request" {
+		t.Errorf("status %d, code %q", apiErr.StatusCode, apiErr.Code)
+	}
+	if apiErr.RequestID != "req_header" {
+		t.Errorf("RequestID = %q, want the X-Request-Id header", apiErr.RequestID)
+	}
+	if len(apiErr.Fields) != 2 || apiErr.Fields[0].Param != "client_email" {
+		t.Errorf("Fields = %+v", apiErr.Fields)
+	}
+	if !errors.Is(err, ErrInvalidRequest) || errors.Is(err, ErrNotFound) {
+		t.Errorf("errors.Is() matched the wrong sentinel for %v", err)
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	tests := []struct {
+		err  *Error
+		want error
+	}{
+		{&Error{StatusCode: http.StatusUnauthorized}, ErrUnauthorized},
+		{&Error{StatusCode: http.StatusNotFound}, ErrNotFound},
+		{&Error{StatusCode: http.StatusTooManyRequests}, ErrRateLimited},
+		{&Error{StatusCode: http.StatusBadRequest}, ErrInvalidRequest},
+		{&Error{StatusCode: http.StatusPaymentRequired}, ErrStripe},
+		{&Error{StatusCode: http.StatusBadRequest, Code: "card_declined"}, ErrStripe},
+	}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.want) {
+			t.Errorf("errors.Is(%d %q, %v) = false", tt.err.StatusCode, tt.err.Code, tt.want)
+		}
+	}
+
+	// 401s keep the hint to log in again
+	err := &Error{StatusCode: http.StatusUnauthorized, Message: "Token has been revoked"}
+	if err.Error() != ErrUnauthorized.Error() {
+		t.Errorf("Error() = %q, want %q", err.Error(), ErrUnauthorized.Error())
+	}
+}
@@ -0,0 +1,284 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Invoice statuses
+const (
+	StatusDraft         = "draft"
+	StatusOpen          = "open"
+	StatusPaid          = "paid"
+	StatusVoid          = "void"
+	StatusUncollectible = "uncollectible"
+)
+
+// Statuses lists every invoice status, in lifecycle order
+var Statuses = []string{StatusDraft, StatusOpen, StatusPaid, StatusVoid, StatusUncollectible}
+
+// Invoice is an invoice as returned by the list and detail endpoints
+type Invoice struct {
+	ID          string     `json:"id"`
+	Number      string     `json:"number,omitempty"`
+	Status      string     `json:"status"`
+	ClientName  string     `json:"client_name,omitempty"`
+	ClientEmail string     `json:"client_email,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Currency    string     `json:"currency"`
+	Subtotal    int64      `json:"subtotal"`    // Total in cents before tax
+	Tax         int64      `json:"tax"`         // Tax in cents
+	Total       int64      `json:"total"`       // Total in cents, including tax
+	AmountDue   int64      `json:"amount_due"`  // Amount still owed in cents
+	AmountPaid  int64      `json:"amount_paid"` // Amount paid so far in cents
+	Created     time.Time  `json:"created"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	PaymentLink string     `json:"payment_link,omitempty"`
+	PDFUrl      string     `json:"pdf_url,omitempty"`
+	LineItems   []LineItem `json:"line_items,omitempty"`
+	Events      []Event    `json:"events,omitempty"` // Only returned by GetInvoice
+}
+
+// Event is an entry in an invoice's timeline
+type Event struct {
+	Type   string    `json:"type"`             // e.g., "created", "sent", "viewed", "paid"
+	Time   time.Time `json:"time"`             // When it happened
+	Detail string    `json:"detail,omitempty"` // Extra context, e.g. the recipient or amount
+}
+
+// ListInvoicesParams filters and pages an invoice listing.
+// Zero values are left out of the query.
+type ListInvoicesParams struct {
+	Status        string    // One of the Status constants
+	Client        string    // Client name or email
+	Currency      string    // e.g., "usd"
+	CreatedAfter  time.Time // Inclusive lower bound
+	CreatedBefore time.Time // Exclusive upper bound
+	Limit         int       // Page size
+	Cursor        string    // NextCursor from a previous page
+}
+
+// InvoiceList is one page of invoices
+type InvoiceList struct {
+	Data       []Invoice `json:"data"`
+	HasMore    bool      `json:"has_more"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// ListInvoices returns a page of invoices matching the given filters
+func (c *Client) ListInvoices(params ListInvoicesParams) (*InvoiceList, error) {
+	return c.ListInvoicesContext(context.Background(), params)
+}
+
+// ListInvoicesContext is ListInvoices with a context that cancels the request
+func (c *Client) ListInvoicesContext(ctx context.Context, params ListInvoicesParams) (*InvoiceList, error) {
+	query := url.Values{}
+	if params.Status != "" {
+		query.Set("status", params.Status)
+	}
+	if params.Client != "" {
+		query.Set("client", params.Client)
+	}
+	if params.Currency != "" {
+		query.Set("currency", params.Currency)
+	}
+	if !params.CreatedAfter.IsZero() {
+		query.Set("created_after", params.CreatedAfter.Format(time.RFC3339))
+	}
+	if !params.CreatedBefore.IsZero() {
+		query.Set("created_before", params.CreatedBefore.Format(time.RFC3339))
+	}
+	if params.Limit > 0 {
+		query.Set("limit", strconv.Itoa(params.Limit))
+	}
+	if params.Cursor != "" {
+		query.Set("cursor", params.Cursor)
+	}
+
+	path := "/api/v1/invoices"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := c.request(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var list InvoiceList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &list, nil
+}
+
+// GetInvoice returns a single invoice with its line items and event timeline
+func (c *Client) GetInvoice(id string) (*Invoice, error) {
+	return c.GetInvoiceContext(context.Background(), id)
+}
+
+// GetInvoiceContext is GetInvoice with a context that cancels the request
+func (c *Client) GetInvoiceContext(ctx context.Context, id string) (*Invoice, error) {
+	resp, err := c.request(ctx, "GET", "/api/v1/invoices/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var invoice Invoice
+	if err := json.NewDecoder(resp.Body).Decode(&invoice); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &invoice, nil
+}
+
+// InvoiceUpdate changes fields on a draft invoice. Nil fields are left as
+// they are; a non-nil LineItems replaces every line on the invoice.
+type InvoiceUpdate struct {
+	Amount      *int64     `json:"amount,omitempty"`
+	ClientName  *string    `json:"client_name,omitempty"`
+	ClientEmail *string    `json:"client_email,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	LineItems   []LineItem `json:"line_items,omitempty"`
+}
+
+// UpdateInvoice edits a draft invoice and returns the updated invoice
+func (c *Client) UpdateInvoice(id string, update InvoiceUpdate) (*Invoice, error) {
+	return c.UpdateInvoiceContext(context.Background(), id, update)
+}
+
+// UpdateInvoiceContext is UpdateInvoice with a context that cancels the request
+func (c *Client) UpdateInvoiceContext(ctx context.Context, id string, update InvoiceUpdate) (*Invoice, error) {
+	body, err := json.Marshal(update)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.request(ctx, "PATCH", "/api/v1/invoices/"+url.PathEscape(id), body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var invoice Invoice
+	if err := json.NewDecoder(resp.Body).Decode(&invoice); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &invoice, nil
+}
+
+// SendInvoice finalizes a draft if needed and emails it to the client
+func (c *Client) SendInvoice(id string) (*InvoiceResponse, error) {
+	return c.SendInvoiceContext(context.Background(), id)
+}
+
+// SendInvoiceContext is SendInvoice with a context that cancels the request
+func (c *Client) SendInvoiceContext(ctx context.Context, id string) (*InvoiceResponse, error) {
+	resp, err := c.request(ctx, "POST", "/api/v1/invoices/"+url.PathEscape(id)+"/send", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var result InvoiceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// FinalizeInvoice finalizes a draft invoice so it can be paid
+func (c *Client) FinalizeInvoice(id string) (*Invoice, error) {
+	return c.FinalizeInvoiceContext(context.Background(), id)
+}
+
+// FinalizeInvoiceContext is FinalizeInvoice with a context that cancels the request
+func (c *Client) FinalizeInvoiceContext(ctx context.Context, id string) (*Invoice, error) {
+	return c.invoiceAction(ctx, id, "finalize")
+}
+
+// VoidInvoice voids a finalized invoice that should not have been issued
+func (c *Client) VoidInvoice(id string) (*Invoice, error) {
+	return c.VoidInvoiceContext(context.Background(), id)
+}
+
+// VoidInvoiceContext is VoidInvoice with a context that cancels the request
+func (c *Client) VoidInvoiceContext(ctx context.Context, id string) (*Invoice, error) {
+	return c.invoiceAction(ctx, id, "void")
+}
+
+// MarkUncollectible marks an open invoice as unlikely to be paid
+func (c *Client) MarkUncollectible(id string) (*Invoice, error) {
+	return c.MarkUncollectibleContext(context.Background(), id)
+}
+
+// MarkUncollectibleContext is MarkUncollectible with a context that cancels the request
+func (c *Client) MarkUncollectibleContext(ctx context.Context, id string) (*Invoice, error) {
+	return c.invoiceAction(ctx, id, "mark_uncollectible")
+}
+
+// DeleteInvoice permanently deletes a draft invoice
+func (c *Client) DeleteInvoice(id string) error {
+	return c.DeleteInvoiceContext(context.Background(), id)
+}
+
+// DeleteInvoiceContext is DeleteInvoice with a context that cancels the request
+func (c *Client) DeleteInvoiceContext(ctx context.Context, id string) error {
+	resp, err := c.request(ctx, "DELETE", "/api/v1/invoices/"+url.PathEscape(id), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
+
+// invoiceAction posts a lifecycle action and returns the updated invoice
+func (c *Client) invoiceAction(ctx context.Context, id, action string) (*Invoice, error) {
+	resp, err := c.request(ctx, "POST", "/api/v1/invoices/"+url.PathEscape(id)+"/"+action, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var invoice Invoice
+	if err := json.NewDecoder(resp.Body).Decode(&invoice); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &invoice, nil
+}
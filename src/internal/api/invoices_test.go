@@ -0,0 +1,288 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestListInvoices(t *testing.T) {
+	after := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v1/invoices" {
+			t.Errorf("expected /api/v1/invoices, got %s", r.URL.Path)
+		}
+
+		q := r.URL.Query()
+		if q.Get("status") != "open" {
+			t.Errorf("expected status=open, got %q", q.Get("status"))
+		}
+		if q.Get("created_after") != "2026-01-01T00:00:00Z" {
+			t.Errorf("unexpected created_after %q", q.Get("created_after"))
+		}
+		if q.Has("created_before") {
+			t.Errorf("created_before should be omitted when zero")
+		}
+		if q.Get("limit") != "2" {
+			t.Errorf("expected limit=2, got %q", q.Get("limit"))
+		}
+
+		resp := InvoiceList{
+			Data: []Invoice{
+				{ID: "inv_1", Status: StatusOpen, Currency: "usd", Total: 10000},
+				{ID: "inv_2", Status: StatusOpen, Currency: "usd", Total: 2500},
+			},
+			HasMore:    true,
+			NextCursor: "inv_2",
+		}
+		if q.Get("cursor") == "inv_2" {
+			resp = InvoiceList{Data: []Invoice{{ID: "inv_3", Status: StatusOpen}}}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: http.DefaultClient,
+	}
+
+	params := ListInvoicesParams{Status: StatusOpen, CreatedAfter: after, Limit: 2}
+	page, err := client.ListInvoices(params)
+	if err != nil {
+		t.Fatalf("ListInvoices() error = %v", err)
+	}
+	if len(page.Data) != 2 || !page.HasMore || page.NextCursor != "inv_2" {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+
+	params.Cursor = page.NextCursor
+	page, err = client.ListInvoices(params)
+	if err != nil {
+		t.Fatalf("ListInvoices() error = %v", err)
+	}
+	if len(page.Data) != 1 || page.Data[0].ID != "inv_3" || page.HasMore {
+		t.Errorf("unexpected second page: %+v", page)
+	}
+}
+
+func TestGetInvoice(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v1/invoices/inv_123" {
+			t.Errorf("expected /api/v1/invoices/inv_123, got %s", r.URL.Path)
+		}
+
+		json.NewEncoder(w).Encode(Invoice{
+			ID:         "inv_123",
+			Status:     StatusPaid,
+			Currency:   "usd",
+			Total:      10000,
+			AmountPaid: 10000,
+			LineItems:  []LineItem{{Description: "Design", Quantity: 1, UnitAmount: 10000}},
+			Events: []Event{
+				{Type: "created", Time: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)},
+				{Type: "paid", Time: time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: http.DefaultClient,
+	}
+
+	invoice, err := client.GetInvoice("inv_123")
+	if err != nil {
+		t.Fatalf("GetInvoice() error = %v", err)
+	}
+	if invoice.Status != StatusPaid || invoice.AmountPaid != 10000 {
+		t.Errorf("unexpected invoice: %+v", invoice)
+	}
+	if len(invoice.LineItems) != 1 || len(invoice.Events) != 2 {
+		t.Errorf("expected 1 line item and 2 events, got %d and %d", len(invoice.LineItems), len(invoice.Events))
+	}
+}
+
+func TestGetInvoiceNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "not_found", Message: "Invoice not found"})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: http.DefaultClient,
+	}
+
+	if _, err := client.GetInvoice("inv_missing"); err == nil || err.Error() != "Invoice not found" {
+		t.Errorf("GetInvoice() error = %v, want 'Invoice not found'", err)
+	}
+}
+
+func TestInvoiceActions(t *testing.T) {
+	tests := []struct {
+		name       string
+		call       func(c *Client) (*Invoice, error)
+		path       string
+		wantStatus string
+	}{
+		{"finalize", func(c *Client) (*Invoice, error) { return c.FinalizeInvoice("inv_1") }, "/api/v1/invoices/inv_1/finalize", StatusOpen},
+		{"void", func(c *Client) (*Invoice, error) { return c.VoidInvoice("inv_1") }, "/api/v1/invoices/inv_1/void", StatusVoid},
+		{"uncollectible", func(c *Client) (*Invoice, error) { return c.MarkUncollectible("inv_1") }, "/api/v1/invoices/inv_1/mark_uncollectible", StatusUncollectible},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "POST" {
+					t.Errorf("expected POST, got %s", r.Method)
+				}
+				if r.URL.Path != tt.path {
+					t.Errorf("expected %s, got %s", tt.path, r.URL.Path)
+				}
+				json.NewEncoder(w).Encode(Invoice{ID: "inv_1", Status: tt.wantStatus})
+			}))
+			defer server.Close()
+
+			client := &Client{
+				baseURL:    server.URL,
+				token:      "test-token",
+				httpClient: http.DefaultClient,
+			}
+
+			invoice, err := tt.call(client)
+			if err != nil {
+				t.Fatalf("%s error = %v", tt.name, err)
+			}
+			if invoice.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", invoice.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestInvoiceActionConflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid_state", Message: "Invoice is already paid"})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: http.DefaultClient,
+	}
+
+	if _, err := client.VoidInvoice("inv_1"); err == nil || err.Error() != "Invoice is already paid" {
+		t.Errorf("VoidInvoice() error = %v, want 'Invoice is already paid'", err)
+	}
+}
+
+func TestDeleteInvoice(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("expected DELETE, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v1/invoices/inv_1" {
+			t.Errorf("expected /api/v1/invoices/inv_1, got %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: http.DefaultClient,
+	}
+
+	if err := client.DeleteInvoice("inv_1"); err != nil {
+		t.Errorf("DeleteInvoice() error = %v", err)
+	}
+}
+
+func TestUpdateInvoice(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" {
+			t.Errorf("expected PATCH, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v1/invoices/inv_1" {
+			t.Errorf("expected /api/v1/invoices/inv_1, got %s", r.URL.Path)
+		}
+
+		// Only the changed fields should be sent
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if len(body) != 2 || body["amount"] != float64(5000) || body["description"] != "Revised" {
+			t.Errorf("unexpected update body: %v", body)
+		}
+
+		json.NewEncoder(w).Encode(Invoice{ID: "inv_1", Status: StatusDraft, Total: 5000, Description: "Revised"})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: http.DefaultClient,
+	}
+
+	amount := int64(5000)
+	desc := "Revised"
+	invoice, err := client.UpdateInvoice("inv_1", InvoiceUpdate{Amount: &amount, Description: &desc})
+	if err != nil {
+		t.Fatalf("UpdateInvoice() error = %v", err)
+	}
+	if invoice.Total != 5000 {
+		t.Errorf("Total = %d, want 5000", invoice.Total)
+	}
+}
+
+func TestSendInvoice(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v1/invoices/inv_1/send" {
+			t.Errorf("expected /api/v1/invoices/inv_1/send, got %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(InvoiceResponse{
+			ID:          "inv_1",
+			Status:      StatusOpen,
+			PaymentLink: "https://pay.stripe.com/inv_1",
+			EmailSent:   true,
+		})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: http.DefaultClient,
+	}
+
+	result, err := client.SendInvoice("inv_1")
+	if err != nil {
+		t.Fatalf("SendInvoice() error = %v", err)
+	}
+	if !result.EmailSent || result.PaymentLink == "" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
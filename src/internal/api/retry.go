@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	mathrand "math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Retry policy for connection errors, 429 and 5xx responses
+const (
+	maxAttempts   = 4 // Including the first try
+	baseDelay     = 500 * time.Millisecond
+	maxDelay      = 8 * time.Second  // Cap on the exponential backoff
+	maxRetryAfter = 60 * time.Second // Longer Retry-After waits aren't retried
+)
+
+// Replaced in tests
+var (
+	sleep  = sleepContext
+	jitter = mathrand.Float64
+)
+
+// sleepContext waits for d, returning early with the context's error if
+// it is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// mutating reports whether a method changes state on the server, and so
+// needs an idempotency key to be retried safely
+func mutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryable reports whether a response status is worth retrying
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// backoff returns how long to wait before retry number attempt (starting at
+// 1): exponential from baseDelay, capped at maxDelay, with the upper half
+// randomized so clients that failed together don't retry together
+func backoff(attempt int) time.Duration {
+	delay := baseDelay << (attempt - 1)
+	if delay > maxDelay || delay <= 0 {
+		delay = maxDelay
+	}
+	return delay/2 + time.Duration(jitter()*float64(delay/2))
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP
+// date. It reports false when the header is missing or invalid.
+func retryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		if wait := at.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// newIdempotencyKey returns a random UUID (version 4)
+func newIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate idempotency key: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/forrestcai35/lane/internal/testutil"
+)
+
+// recordSleeps replaces sleep for the test and returns the waits it was
+// asked for, with jitter fixed at its midpoint
+func recordSleeps(t *testing.T) *[]time.Duration {
+	t.Helper()
+
+	original := jitter
+	jitter = func() float64 { return 0.5 }
+	t.Cleanup(func() { jitter = original })
+	return testutil.RecordSleeps(t, &sleep)
+}
+
+func TestRetryWithIdempotencyKey(t *testing.T) {
+	waits := recordSleeps(t)
+
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		switch len(keys) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(InvoiceResponse{ID: "inv_123"})
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{baseURL: server.URL, token: "test-token", httpClient: http.DefaultClient}
+	result, err := client.CreateInvoice(InvoiceRequest{Amount: 1000, Currency: "usd"})
+	if err != nil {
+		t.Fatalf("CreateInvoice() error = %v", err)
+	}
+	if result.ID != "inv_123" {
+		t.Errorf("ID = %q, want inv_123", result.ID)
+	}
+
+	if len(keys) != 3 || keys[0] == "" || keys[1] != keys[0] || keys[2] != keys[0] {
+		t.Errorf("Idempotency-Key headers = %q, want one key reused on every attempt", keys)
+	}
+	want := []time.Duration{375 * time.Millisecond, 3 * time.Second}
+	if len(*waits) != len(want) || (*waits)[0] != want[0] || (*waits)[1] != want[1] {
+		t.Errorf("waits = %v, want %v", *waits, want)
+	}
+
+	// A new operation gets a new key
+	client.CreateInvoice(InvoiceRequest{Amount: 1000, Currency: "usd"})
+	if keys[3] == keys[0] {
+		t.Error("second CreateInvoice() reused the first key")
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	waits := recordSleeps(t)
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.Header.Get("Idempotency-Key") != "" {
+			t.Error("GET request sent an Idempotency-Key")
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := &Client{baseURL: server.URL, token: "test-token", httpClient: http.DefaultClient}
+	if _, err := client.GetCurrentUser(); err == nil {
+		t.Fatal("GetCurrentUser() expected error")
+	}
+	if attempts != maxAttempts || len(*waits) != maxAttempts-1 {
+		t.Errorf("attempts = %d, waits = %v", attempts, *waits)
+	}
+
+	// Connection errors are retried too
+	server.Close()
+	*waits = nil
+	if _, err := client.GetCurrentUser(); err == nil {
+		t.Fatal("GetCurrentUser() expected error")
+	}
+	if len(*waits) != maxAttempts-1 {
+		t.Errorf("waits = %v, want %d retries", *waits, maxAttempts-1)
+	}
+}
+
+func TestNoRetry(t *testing.T) {
+	waits := recordSleeps(t)
+
+	cases := map[string]func(w http.ResponseWriter){
+		"client error": func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusBadRequest)
+		},
+		"long Retry-After": func(w http.ResponseWriter) {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		},
+	}
+	for name, respond := range cases {
+		t.Run(name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				respond(w)
+			}))
+			defer server.Close()
+
+			client := &Client{baseURL: server.URL, token: "test-token", httpClient: http.DefaultClient}
+			if _, err := client.CreateInvoice(InvoiceRequest{Amount: 1000}); err == nil {
+				t.Error("CreateInvoice() expected error")
+			}
+			if attempts != 1 {
+				t.Errorf("attempts = %d, want 1", attempts)
+			}
+		})
+	}
+	if len(*waits) != 0 {
+		t.Errorf("waits = %v, want none", *waits)
+	}
+}
+
+func TestRequestCancelled(t *testing.T) {
+	waits := recordSleeps(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &Client{baseURL: server.URL, token: "test-token", httpClient: http.DefaultClient}
+	if _, err := client.CreateInvoiceContext(ctx, InvoiceRequest{Amount: 1000}); !errors.Is(err, context.Canceled) {
+		t.Errorf("CreateInvoiceContext() error = %v, want context.Canceled", err)
+	}
+	if attempts != 1 || len(*waits) != 0 {
+		t.Errorf("attempts = %d, waits = %v, want no retries after cancelling", attempts, *waits)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"Sat, 17 Oct 2026 12:00:10 GMT", 10 * time.Second, true},
+		{"Sat, 17 Oct 2026 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := retryAfter(tt.header, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%q) = %v, %v, want %v, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 1; attempt <= 10; attempt++ {
+		delay := baseDelay << (attempt - 1)
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+		if got := backoff(attempt); got < delay/2 || got > delay {
+			t.Errorf("backoff(%d) = %v, want between %v and %v", attempt, got, delay/2, delay)
+		}
+	}
+}
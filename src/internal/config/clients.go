@@ -0,0 +1,177 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// clientsFile is the local address book
+const clientsFile = "clients.json"
+
+// Client is an entry in the local address book
+type Client struct {
+	Alias    string   `json:"alias"`              // Short name used with --client, e.g. "acme"
+	Name     string   `json:"name"`               // Billing name, e.g. "Acme Corp"
+	Emails   []string `json:"emails,omitempty"`   // Billing emails; the first is the recipient
+	Currency string   `json:"currency,omitempty"` // Default currency code
+	Terms    *int     `json:"terms,omitempty"`    // Default payment terms in days
+	Address  string   `json:"address,omitempty"`  // Billing address
+	TaxID    string   `json:"tax_id,omitempty"`   // VAT number or other tax ID
+
+	CustomerID string `json:"customer_id,omitempty"` // Linked Stripe customer (set by 'lane clients sync')
+}
+
+// ValidateAlias checks that an alias is usable as a --client value
+func ValidateAlias(alias string) error {
+	if alias == "" {
+		return fmt.Errorf("alias is required")
+	}
+	for _, r := range alias {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return fmt.Errorf("invalid alias %q (use lowercase letters, digits, - and _)", alias)
+		}
+	}
+	return nil
+}
+
+// LoadClients returns every client in the address book, sorted by alias
+func LoadClients() ([]Client, error) {
+	dir, err := profileDir()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, clientsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not read address book: %w", err)
+	}
+
+	var clients []Client
+	if err := json.Unmarshal(data, &clients); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", clientsFile, err)
+	}
+
+	sort.Slice(clients, func(i, j int) bool { return clients[i].Alias < clients[j].Alias })
+	return clients, nil
+}
+
+// SaveClients replaces the address book
+func SaveClients(clients []Client) error {
+	dir, err := profileDir()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("could not create config directory: %w", err)
+	}
+
+	sort.Slice(clients, func(i, j int) bool { return clients[i].Alias < clients[j].Alias })
+	data, err := json.MarshalIndent(clients, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode address book: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, clientsFile), data, 0600); err != nil {
+		return fmt.Errorf("could not save address book: %w", err)
+	}
+
+	return nil
+}
+
+// FindClient looks up a client by alias, or failing that by name.
+// Both are matched case-insensitively. It returns nil if there is no match.
+func FindClient(aliasOrName string) (*Client, error) {
+	clients, err := LoadClients()
+	if err != nil {
+		return nil, err
+	}
+
+	key := strings.TrimSpace(aliasOrName)
+	for i := range clients {
+		if strings.EqualFold(clients[i].Alias, key) {
+			return &clients[i], nil
+		}
+	}
+	for i := range clients {
+		if strings.EqualFold(clients[i].Name, key) {
+			return &clients[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// AliasFor derives an alias from a name, e.g. "Acme Corp." -> "acme-corp"
+func AliasFor(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case b.Len() > 0 && !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// AddClient adds a new client to the address book
+func AddClient(c Client) error {
+	if err := ValidateAlias(c.Alias); err != nil {
+		return err
+	}
+
+	clients, err := LoadClients()
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range clients {
+		if existing.Alias == c.Alias {
+			return fmt.Errorf("client %q already exists (use 'lane clients edit %s')", c.Alias, c.Alias)
+		}
+	}
+
+	return SaveClients(append(clients, c))
+}
+
+// UpdateClient replaces the client with the same alias
+func UpdateClient(c Client) error {
+	clients, err := LoadClients()
+	if err != nil {
+		return err
+	}
+
+	for i := range clients {
+		if clients[i].Alias == c.Alias {
+			clients[i] = c
+			return SaveClients(clients)
+		}
+	}
+	return fmt.Errorf("no client with alias %q", c.Alias)
+}
+
+// RemoveClient deletes a client from the address book
+func RemoveClient(alias string) error {
+	clients, err := LoadClients()
+	if err != nil {
+		return err
+	}
+
+	for i := range clients {
+		if clients[i].Alias == alias {
+			return SaveClients(append(clients[:i], clients[i+1:]...))
+		}
+	}
+	return fmt.Errorf("no client with alias %q", alias)
+}
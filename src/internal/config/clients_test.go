@@ -0,0 +1,105 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClients(t *testing.T) {
+	home := setTempHome(t)
+
+	terms := 30
+	acme := Client{
+		Alias:    "acme",
+		Name:     "Acme Corp",
+		Emails:   []string{"ap@acme.com", "cfo@acme.com"},
+		Currency: "eur",
+		Terms:    &terms,
+		TaxID:    "DE123456789",
+	}
+
+	t.Run("empty address book", func(t *testing.T) {
+		clients, err := LoadClients()
+		if err != nil {
+			t.Fatalf("LoadClients() error = %v", err)
+		}
+		if len(clients) != 0 {
+			t.Errorf("LoadClients() = %v, want empty", clients)
+		}
+	})
+
+	t.Run("add and find", func(t *testing.T) {
+		if err := AddClient(acme); err != nil {
+			t.Fatalf("AddClient() error = %v", err)
+		}
+		if err := AddClient(Client{Alias: "globex", Name: "Globex"}); err != nil {
+			t.Fatalf("AddClient() error = %v", err)
+		}
+
+		byAlias, err := FindClient("ACME")
+		if err != nil || byAlias == nil || byAlias.Name != "Acme Corp" {
+			t.Fatalf("FindClient(ACME) = %v, %v", byAlias, err)
+		}
+		if byAlias.Terms == nil || *byAlias.Terms != 30 || len(byAlias.Emails) != 2 {
+			t.Errorf("FindClient() lost fields: %+v", byAlias)
+		}
+
+		byName, err := FindClient("acme corp")
+		if err != nil || byName == nil || byName.Alias != "acme" {
+			t.Errorf("FindClient(acme corp) = %v, %v", byName, err)
+		}
+
+		missing, err := FindClient("initech")
+		if err != nil || missing != nil {
+			t.Errorf("FindClient(initech) = %v, %v, want nil", missing, err)
+		}
+	})
+
+	t.Run("file has correct permissions", func(t *testing.T) {
+		info, err := os.Stat(filepath.Join(home, ".lane", "clients.json"))
+		if err != nil {
+			t.Fatalf("failed to stat address book: %v", err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("address book permissions = %o, want 0600", perm)
+		}
+	})
+
+	t.Run("rejects duplicates and bad aliases", func(t *testing.T) {
+		if err := AddClient(acme); err == nil {
+			t.Error("AddClient() should reject a duplicate alias")
+		}
+		if err := AddClient(Client{Alias: "Acme Corp"}); err == nil {
+			t.Error("AddClient() should reject an alias with spaces")
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		updated := acme
+		updated.Currency = "gbp"
+		if err := UpdateClient(updated); err != nil {
+			t.Fatalf("UpdateClient() error = %v", err)
+		}
+		got, _ := FindClient("acme")
+		if got.Currency != "gbp" {
+			t.Errorf("Currency = %q, want gbp", got.Currency)
+		}
+		if err := UpdateClient(Client{Alias: "initech"}); err == nil {
+			t.Error("UpdateClient() should fail for an unknown alias")
+		}
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		if err := RemoveClient("acme"); err != nil {
+			t.Fatalf("RemoveClient() error = %v", err)
+		}
+		clients, _ := LoadClients()
+		if len(clients) != 1 || clients[0].Alias != "globex" {
+			t.Errorf("LoadClients() after remove = %v", clients)
+		}
+		if err := RemoveClient("acme"); err == nil {
+			t.Error("RemoveClient() should fail for a missing alias")
+		}
+	})
+}
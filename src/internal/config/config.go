@@ -1,9 +1,13 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const (
@@ -11,8 +15,17 @@ const (
 	DefaultAPIURL = "https://lane-website.netlify.app" // No trailing slash
 	EnvAPIURL     = "LANE_API_URL"                     // Override for development
 	EnvAuthToken  = "LANE_TOKEN"                       // Auth token (set by 'lane login')
+	EnvLocale     = "LANE_LOCALE"                      // Number format for amounts
+	EnvDebug      = "LANE_DEBUG"                       // Log API requests
+
+	TokenSourceEnv  = "env"
+	DefaultLocale   = "en"
+	DefaultCurrency = "usd"
 )
 
+// ErrNotLoggedIn is returned when there is no token for the active profile
+var ErrNotLoggedIn = errors.New("not logged in. Run 'lane login' to authenticate")
+
 // configDir returns the Lane config directory path
 func configDir() (string, error) {
 	home, err := os.UserHomeDir()
@@ -24,69 +37,154 @@ func configDir() (string, error) {
 
 // GetAPIURL returns the Lane API URL
 func GetAPIURL() string {
-	if url := os.Getenv(EnvAPIURL); url != "" {
-		return url
+	return strings.TrimRight(getSetting("api_url"), "/")
+}
+
+// GetDefaultCurrency returns the currency used when none is given
+func GetDefaultCurrency() string {
+	return strings.ToLower(getSetting("currency"))
+}
+
+// GetLocale returns the locale used to parse amounts
+func GetLocale() string {
+	return getSetting("locale")
+}
+
+// GetSendByDefault reports whether new invoices are emailed without --send
+func GetSendByDefault() bool {
+	send, _ := strconv.ParseBool(getSetting("send"))
+	return send
+}
+
+// GetClipboard reports whether payment links are copied to the clipboard
+func GetClipboard() bool {
+	clipboard, err := strconv.ParseBool(getSetting("clipboard"))
+	return err != nil || clipboard
+}
+
+// GetOutputFormat returns the default output format for listings
+func GetOutputFormat() string {
+	return strings.ToLower(getSetting("output"))
+}
+
+// GetTheme returns the color theme: auto, dark, light or none
+func GetTheme() string {
+	return strings.ToLower(getSetting("theme"))
+}
+
+// GetDebug reports whether API requests are logged
+func GetDebug() bool {
+	debug, _ := strconv.ParseBool(getSetting("debug"))
+	return debug
+}
+
+// GetTimeout returns the API request timeout
+func GetTimeout() time.Duration {
+	timeout, err := time.ParseDuration(getSetting("timeout"))
+	if err != nil || timeout <= 0 {
+		return 30 * time.Second
 	}
-	return DefaultAPIURL
+	return timeout
 }
 
 // GetAuthToken returns the stored auth token
 func GetAuthToken() (string, error) {
+	creds, err := GetCredentials()
+	if err != nil {
+		return "", err
+	}
+	return creds.Token, nil
+}
+
+// GetCredentials returns the auth token along with its refresh token and
+// expiry, when the login provided them
+func GetCredentials() (Credentials, error) {
 	// First check env var (for CI/scripts)
 	if token := os.Getenv(EnvAuthToken); token != "" {
-		return token, nil
+		return Credentials{Token: token}, nil
 	}
+	return GetStoredCredentials()
+}
 
-	// Then check token file
-	dir, err := configDir()
+// GetStoredCredentials returns the login in the credential store, ignoring
+// LANE_TOKEN
+func GetStoredCredentials() (Credentials, error) {
+	store, err := GetCredentialStore()
 	if err != nil {
-		return "", err
+		return Credentials{}, err
 	}
 
-	tokenPath := filepath.Join(dir, "token")
-	data, err := os.ReadFile(tokenPath)
+	value, err := store.Get()
+	if errors.Is(err, ErrNoCredential) {
+		value, err = migrateLegacyToken(store)
+	}
+	if errors.Is(err, ErrNoCredential) {
+		return Credentials{}, ErrNotLoggedIn
+	}
 	if err != nil {
-		if os.IsNotExist(err) {
-			return "", fmt.Errorf("not logged in. Run 'lane login' to authenticate")
-		}
-		return "", fmt.Errorf("could not read token: %w", err)
+		return Credentials{}, err
 	}
-
-	return string(data), nil
+	return decodeCredentials(value), nil
 }
 
-// SaveAuthToken stores the auth token
-func SaveAuthToken(token string) error {
-	dir, err := configDir()
-	if err != nil {
-		return err
+// migrateLegacyToken moves a plaintext ~/.lane/token written by older
+// versions into store. The token is still returned if the move fails.
+func migrateLegacyToken(store CredentialStore) (string, error) {
+	if store.Name() == StoreFile {
+		return "", ErrNoCredential
 	}
 
-	if err := os.MkdirAll(dir, 0700); err != nil {
-		return fmt.Errorf("could not create config directory: %w", err)
+	legacy := fileStore{GetProfile()}
+	token, err := legacy.Get()
+	if err != nil {
+		return "", err
 	}
 
-	tokenPath := filepath.Join(dir, "token")
-	if err := os.WriteFile(tokenPath, []byte(token), 0600); err != nil {
-		return fmt.Errorf("could not save token: %w", err)
+	if err := store.Set(token); err == nil {
+		legacy.Delete()
 	}
+	return token, nil
+}
 
-	return nil
+// SaveAuthToken stores the auth token in the credential store
+func SaveAuthToken(token string) error {
+	return SaveCredentials(Credentials{Token: token})
 }
 
-// DeleteAuthToken removes the stored auth token
+// SaveCredentials stores a login in the credential store
+func SaveCredentials(creds Credentials) error {
+	store, err := GetCredentialStore()
+	if err != nil {
+		return err
+	}
+	return store.Set(encodeCredentials(creds))
+}
+
+// DeleteAuthToken removes the stored auth token from the credential store,
+// along with any plaintext token left by older versions
 func DeleteAuthToken() error {
-	dir, err := configDir()
+	store, err := GetCredentialStore()
 	if err != nil {
 		return err
 	}
 
-	tokenPath := filepath.Join(dir, "token")
-	if err := os.Remove(tokenPath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("could not delete token: %w", err)
+	if err := store.Delete(); err != nil {
+		return err
 	}
+	return fileStore{GetProfile()}.Delete()
+}
 
-	return nil
+// GetTokenSource names where GetAuthToken finds the token: "env" for
+// LANE_TOKEN, otherwise the credential store's name
+func GetTokenSource() (string, error) {
+	if os.Getenv(EnvAuthToken) != "" {
+		return TokenSourceEnv, nil
+	}
+	store, err := GetCredentialStore()
+	if err != nil {
+		return "", err
+	}
+	return store.Name(), nil
 }
 
 // IsLoggedIn returns true if the user has a valid auth token
@@ -95,6 +193,27 @@ func IsLoggedIn() bool {
 	return err == nil
 }
 
+// HasStoredCredentials reports whether the active profile's credential
+// store holds a login, ignoring LANE_TOKEN. An encrypted token file is only
+// looked for, not decrypted, so this never asks for the passphrase.
+func HasStoredCredentials() bool {
+	store, err := GetCredentialStore()
+	if err != nil {
+		return false
+	}
+
+	if enc, ok := store.(encryptedFileStore); ok {
+		_, err = enc.read()
+	} else {
+		_, err = store.Get()
+	}
+	if errors.Is(err, ErrNoCredential) && store.Name() != StoreFile {
+		// A plaintext token left by older versions
+		_, err = fileStore{GetProfile()}.Get()
+	}
+	return err == nil
+}
+
 // GetConfigDir returns the config directory path (for display)
 func GetConfigDir() string {
 	dir, _ := configDir()
@@ -7,12 +7,8 @@ import (
 )
 
 func TestGetAPIURL(t *testing.T) {
-	// Save original env value
-	original := os.Getenv(EnvAPIURL)
-	defer os.Setenv(EnvAPIURL, original)
-
 	t.Run("returns default when env not set", func(t *testing.T) {
-		os.Unsetenv(EnvAPIURL)
+		t.Setenv(EnvAPIURL, "")
 		got := GetAPIURL()
 		if got != DefaultAPIURL {
 			t.Errorf("GetAPIURL() = %q, want %q", got, DefaultAPIURL)
@@ -21,7 +17,7 @@ func TestGetAPIURL(t *testing.T) {
 
 	t.Run("returns env value when set", func(t *testing.T) {
 		customURL := "https://custom.example.com"
-		os.Setenv(EnvAPIURL, customURL)
+		t.Setenv(EnvAPIURL, customURL)
 		got := GetAPIURL()
 		if got != customURL {
 			t.Errorf("GetAPIURL() = %q, want %q", got, customURL)
@@ -30,22 +26,13 @@ func TestGetAPIURL(t *testing.T) {
 }
 
 func TestAuthToken(t *testing.T) {
-	// Create a temp directory for testing
-	tmpDir, err := os.MkdirTemp("", "lane-test-*")
-	if err != nil {
-		t.Fatalf("failed to create temp dir: %v", err)
-	}
-	defer os.RemoveAll(tmpDir)
-
-	// Override HOME to use temp directory
-	originalHome := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
-	defer os.Setenv("HOME", originalHome)
+	home := setTempHome(t)
 
 	// Clear any env token
-	originalToken := os.Getenv(EnvAuthToken)
-	os.Unsetenv(EnvAuthToken)
-	defer os.Setenv(EnvAuthToken, originalToken)
+	t.Setenv(EnvAuthToken, "")
+
+	// Use the plaintext file store
+	t.Setenv(EnvCredentialStore, StoreFile)
 
 	t.Run("returns error when not logged in", func(t *testing.T) {
 		_, err := GetAuthToken()
@@ -71,7 +58,7 @@ func TestAuthToken(t *testing.T) {
 	})
 
 	t.Run("token file has correct permissions", func(t *testing.T) {
-		tokenPath := filepath.Join(tmpDir, ".lane", "token")
+		tokenPath := filepath.Join(home, ".lane", "token")
 		info, err := os.Stat(tokenPath)
 		if err != nil {
 			t.Fatalf("failed to stat token file: %v", err)
@@ -102,7 +89,7 @@ func TestAuthToken(t *testing.T) {
 
 	t.Run("env token takes precedence", func(t *testing.T) {
 		envToken := "env-token-xyz"
-		os.Setenv(EnvAuthToken, envToken)
+		t.Setenv(EnvAuthToken, envToken)
 
 		// Save a different file token
 		SaveAuthToken("file-token-abc")
@@ -114,8 +101,6 @@ func TestAuthToken(t *testing.T) {
 		if got != envToken {
 			t.Errorf("GetAuthToken() = %q, want env token %q", got, envToken)
 		}
-
-		os.Unsetenv(EnvAuthToken)
 	})
 }
 
@@ -0,0 +1,423 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zalando/go-keyring"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	EnvCredentialStore = "LANE_CREDENTIAL_STORE" // keyring, encrypted-file or file
+	EnvPassphrase      = "LANE_PASSPHRASE"       // Passphrase for the encrypted-file store
+
+	// Credential store names
+	StoreKeyring       = "keyring"
+	StoreEncryptedFile = "encrypted-file"
+	StoreFile          = "file"
+
+	keyringService = "lane"
+	keyringUser    = "token"
+)
+
+// ErrNoCredential is returned by a store that has no token saved
+var ErrNoCredential = errors.New("no credential stored")
+
+// PassphrasePrompt asks the user for the encrypted-file passphrase when
+// LANE_PASSPHRASE is not set. confirm is true when a new passphrase is being
+// chosen. It is only set when stdin is a terminal; without one, the
+// passphrase has to come from LANE_PASSPHRASE.
+var PassphrasePrompt func(confirm bool) (string, error)
+
+// Credentials is a login as kept in a credential store. Logins that don't
+// expire, tokens saved by older versions and tokens from LANE_TOKEN have
+// only a Token.
+type Credentials struct {
+	Token        string     `json:"token"`
+	RefreshToken string     `json:"refresh_token,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+}
+
+// CanRefresh reports whether the token can be renewed without logging in
+func (c Credentials) CanRefresh() bool {
+	return c.RefreshToken != ""
+}
+
+// encodeCredentials turns credentials into the string a store keeps. A
+// plain token is stored as is, so older versions can read it. Credential
+// helpers never see this value; they are given each field on its own line.
+func encodeCredentials(c Credentials) string {
+	if c.RefreshToken == "" && c.ExpiresAt == nil {
+		return c.Token
+	}
+	data, _ := json.Marshal(c)
+	return string(data)
+}
+
+// decodeCredentials reads credentials written by encodeCredentials
+func decodeCredentials(value string) Credentials {
+	if strings.HasPrefix(value, "{") {
+		var c Credentials
+		if err := json.Unmarshal([]byte(value), &c); err == nil && c.Token != "" {
+			return c
+		}
+	}
+	return Credentials{Token: value}
+}
+
+// CredentialStore is a place the auth token can be kept
+type CredentialStore interface {
+	Name() string
+	Get() (string, error)
+	Set(token string) error
+	Delete() error
+}
+
+// GetCredentialStore returns the store the active profile's auth token is
+// kept in. A configured credential helper always wins; otherwise
+// LANE_CREDENTIAL_STORE picks the store. By default the OS keyring is used
+// when one is reachable, falling back to a passphrase-encrypted file.
+func GetCredentialStore() (CredentialStore, error) {
+	return credentialStoreFor(GetProfile())
+}
+
+// credentialStoreFor returns the credential store for a profile
+func credentialStoreFor(profile string) (CredentialStore, error) {
+	if helper := GetCredentialHelper(); helper != "" {
+		return helperStore{command: helper, profile: profile}, nil
+	}
+
+	setting, err := GetSetting("credential.store")
+	if err != nil {
+		return nil, err
+	}
+	name := strings.ToLower(strings.TrimSpace(setting.Value))
+	switch name {
+	case "", "auto":
+		if keyringAvailable() {
+			return keyringStore{profile}, nil
+		}
+		return encryptedFileStore{profile}, nil
+	case StoreKeyring:
+		return keyringStore{profile}, nil
+	case StoreEncryptedFile:
+		return encryptedFileStore{profile}, nil
+	case StoreFile:
+		return fileStore{profile}, nil
+	default:
+		return nil, fmt.Errorf("unknown credential store %q (expected %s, %s or %s)", name, StoreKeyring, StoreEncryptedFile, StoreFile)
+	}
+}
+
+// keyringAvailable reports whether the OS keyring can be reached. The
+// answer is worked out once per process, since asking takes a round trip
+// to the keyring daemon.
+var keyringAvailable = sync.OnceValue(probeKeyring)
+
+// probeKeyring tries a keyring read to see whether the keyring is there
+func probeKeyring() bool {
+	_, err := keyring.Get(keyringService, keyringUser)
+	return err == nil || errors.Is(err, keyring.ErrNotFound)
+}
+
+// keyringStore keeps the token in the OS keyring: Secret Service over D-Bus
+// on Linux, Keychain on macOS and Credential Manager on Windows
+type keyringStore struct {
+	profile string
+}
+
+func (keyringStore) Name() string { return StoreKeyring }
+
+// user returns the keyring account name, which is "token" for the default
+// profile and "token@<profile>" for the others
+func (k keyringStore) user() string {
+	if k.profile == "" || k.profile == DefaultProfile {
+		return keyringUser
+	}
+	return keyringUser + "@" + k.profile
+}
+
+func (k keyringStore) Get() (string, error) {
+	token, err := keyring.Get(keyringService, k.user())
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNoCredential
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not read token from keyring: %w", err)
+	}
+	return token, nil
+}
+
+func (k keyringStore) Set(token string) error {
+	if err := keyring.Set(keyringService, k.user(), token); err != nil {
+		return fmt.Errorf("could not save token to keyring: %w", err)
+	}
+	return nil
+}
+
+func (k keyringStore) Delete() error {
+	err := keyring.Delete(keyringService, k.user())
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("could not delete token from keyring: %w", err)
+	}
+	return nil
+}
+
+// fileStore keeps the token in plaintext in the profile's token file
+type fileStore struct {
+	profile string
+}
+
+func (fileStore) Name() string { return StoreFile }
+
+func (f fileStore) Get() (string, error) {
+	path, err := profilePath(f.profile, "token")
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNoCredential
+		}
+		return "", fmt.Errorf("could not read token: %w", err)
+	}
+	return string(data), nil
+}
+
+func (f fileStore) Set(token string) error {
+	return writeProfileFile(f.profile, "token", []byte(token))
+}
+
+func (f fileStore) Delete() error {
+	return removeProfileFile(f.profile, "token")
+}
+
+// encryptedFileStore keeps the token in the profile's token.enc, encrypted
+// with AES-256-GCM under a key derived from a passphrase with scrypt
+type encryptedFileStore struct {
+	profile string
+}
+
+// encryptedToken is the on-disk format of token.enc
+type encryptedToken struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+func (encryptedFileStore) Name() string { return StoreEncryptedFile }
+
+func (e encryptedFileStore) Get() (string, error) {
+	enc, err := e.read()
+	if err != nil {
+		return "", err
+	}
+
+	_, token, err := e.unlock(enc)
+	return token, err
+}
+
+func (e encryptedFileStore) Set(token string) error {
+	// Re-encrypt an existing file, e.g. on a token refresh, under the
+	// passphrase it already has rather than asking for a new one
+	var passphrase string
+	enc, err := e.read()
+	switch {
+	case err == nil:
+		if passphrase, _, err = e.unlock(enc); err != nil {
+			return err
+		}
+	case errors.Is(err, ErrNoCredential):
+		if passphrase, err = getPassphrase(e.profile, true); err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+
+	enc = encryptedToken{Version: 1, Salt: make([]byte, 16)}
+	if _, err := rand.Read(enc.Salt); err != nil {
+		return fmt.Errorf("could not generate salt: %w", err)
+	}
+
+	gcm, err := newGCM(passphrase, enc.Salt)
+	if err != nil {
+		return err
+	}
+	enc.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(enc.Nonce); err != nil {
+		return fmt.Errorf("could not generate nonce: %w", err)
+	}
+	enc.Data = gcm.Seal(nil, enc.Nonce, []byte(token), nil)
+
+	data, err := json.Marshal(enc)
+	if err != nil {
+		return fmt.Errorf("could not encode token: %w", err)
+	}
+	if err := writeProfileFile(e.profile, "token.enc", data); err != nil {
+		return err
+	}
+	rememberPassphrase(e.profile, passphrase)
+	return nil
+}
+
+func (e encryptedFileStore) Delete() error {
+	forgetPassphrase(e.profile)
+	return removeProfileFile(e.profile, "token.enc")
+}
+
+// read loads the profile's token.enc, returning ErrNoCredential if there
+// is none
+func (e encryptedFileStore) read() (encryptedToken, error) {
+	var enc encryptedToken
+	path, err := profilePath(e.profile, "token.enc")
+	if err != nil {
+		return enc, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return enc, ErrNoCredential
+		}
+		return enc, fmt.Errorf("could not read token: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return enc, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	return enc, nil
+}
+
+// unlock decrypts enc, returning the passphrase that opened it and the
+// token. The passphrase is cached, so only the first unlock prompts.
+func (e encryptedFileStore) unlock(enc encryptedToken) (string, string, error) {
+	passphrase, err := getPassphrase(e.profile, false)
+	if err != nil {
+		return "", "", err
+	}
+
+	gcm, err := newGCM(passphrase, enc.Salt)
+	if err != nil {
+		return "", "", err
+	}
+	token, err := gcm.Open(nil, enc.Nonce, enc.Data, nil)
+	if err != nil {
+		forgetPassphrase(e.profile)
+		return "", "", fmt.Errorf("could not decrypt token: wrong passphrase?")
+	}
+	rememberPassphrase(e.profile, passphrase)
+	return passphrase, string(token), nil
+}
+
+// newGCM derives an AES-256-GCM cipher from a passphrase and salt
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("could not derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// passphrases caches each profile's passphrase once it has unlocked the
+// token, so a token refresh mid-command doesn't prompt again
+var (
+	passphraseMu sync.Mutex
+	passphrases  = map[string]string{}
+)
+
+func rememberPassphrase(profile, passphrase string) {
+	passphraseMu.Lock()
+	defer passphraseMu.Unlock()
+	passphrases[profile] = passphrase
+}
+
+func forgetPassphrase(profile string) {
+	passphraseMu.Lock()
+	defer passphraseMu.Unlock()
+	delete(passphrases, profile)
+}
+
+// getPassphrase reads the passphrase from LANE_PASSPHRASE, the cache or a
+// prompt
+func getPassphrase(profile string, confirm bool) (string, error) {
+	if passphrase := os.Getenv(EnvPassphrase); passphrase != "" {
+		return passphrase, nil
+	}
+
+	passphraseMu.Lock()
+	passphrase, ok := passphrases[profile]
+	passphraseMu.Unlock()
+	if ok {
+		return passphrase, nil
+	}
+
+	if PassphrasePrompt == nil {
+		return "", fmt.Errorf("a passphrase is required for the encrypted token file. Set %s", EnvPassphrase)
+	}
+
+	passphrase, err := PassphrasePrompt(confirm)
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase cannot be empty")
+	}
+	return passphrase, nil
+}
+
+// profilePath returns the path of a file in a profile's directory
+func profilePath(profile, name string) (string, error) {
+	dir, err := profileDirFor(profile)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// writeProfileFile writes a private file in a profile's directory
+func writeProfileFile(profile, name string, data []byte) error {
+	dir, err := profileDirFor(profile)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("could not create config directory: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+		return fmt.Errorf("could not save %s: %w", name, err)
+	}
+	return nil
+}
+
+// removeProfileFile deletes a file in a profile's directory if it exists
+func removeProfileFile(profile, name string) error {
+	path, err := profilePath(profile, name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not delete %s: %w", name, err)
+	}
+	return nil
+}
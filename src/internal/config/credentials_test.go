@@ -0,0 +1,237 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetCredentialStore(t *testing.T) {
+	mockKeyring()
+
+	tests := []struct {
+		env  string
+		want string
+	}{
+		{"", StoreKeyring},
+		{"auto", StoreKeyring},
+		{"keyring", StoreKeyring},
+		{"Encrypted-File", StoreEncryptedFile},
+		{"file", StoreFile},
+	}
+
+	for _, tt := range tests {
+		t.Setenv(EnvCredentialStore, tt.env)
+		store, err := GetCredentialStore()
+		if err != nil {
+			t.Fatalf("GetCredentialStore() with %q error = %v", tt.env, err)
+		}
+		if store.Name() != tt.want {
+			t.Errorf("GetCredentialStore() with %q = %s, want %s", tt.env, store.Name(), tt.want)
+		}
+	}
+
+	t.Setenv(EnvCredentialStore, "vault")
+	if _, err := GetCredentialStore(); err == nil {
+		t.Error("GetCredentialStore() should reject unknown stores")
+	}
+}
+
+func TestKeyringStore(t *testing.T) {
+	mockKeyring()
+	store := keyringStore{DefaultProfile}
+
+	if _, err := store.Get(); !errors.Is(err, ErrNoCredential) {
+		t.Fatalf("Get() error = %v, want ErrNoCredential", err)
+	}
+	if err := store.Set("secret-token"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got, err := store.Get(); err != nil || got != "secret-token" {
+		t.Errorf("Get() = %q, %v, want secret-token", got, err)
+	}
+	if err := store.Delete(); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := store.Delete(); err != nil {
+		t.Errorf("Delete() of a missing token error = %v", err)
+	}
+}
+
+func TestEncryptedFileStore(t *testing.T) {
+	home := setTempHome(t)
+
+	t.Setenv(EnvPassphrase, "correct horse")
+
+	store := encryptedFileStore{DefaultProfile}
+
+	if _, err := store.Get(); !errors.Is(err, ErrNoCredential) {
+		t.Fatalf("Get() error = %v, want ErrNoCredential", err)
+	}
+	if err := store.Set("secret-token"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	path := filepath.Join(home, ".lane", "token.enc")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read token file: %v", err)
+	}
+	if strings.Contains(string(data), "secret-token") {
+		t.Error("token file contains the plaintext token")
+	}
+
+	if got, err := store.Get(); err != nil || got != "secret-token" {
+		t.Errorf("Get() = %q, %v, want secret-token", got, err)
+	}
+
+	t.Setenv(EnvPassphrase, "wrong")
+	if _, err := store.Get(); err == nil {
+		t.Error("Get() with the wrong passphrase should fail")
+	}
+
+	t.Setenv(EnvPassphrase, "")
+	PassphrasePrompt = func(confirm bool) (string, error) { return "correct horse", nil }
+	defer func() { PassphrasePrompt = nil }()
+	if got, err := store.Get(); err != nil || got != "secret-token" {
+		t.Errorf("Get() with prompt = %q, %v, want secret-token", got, err)
+	}
+
+	if err := store.Delete(); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("token file still exists after Delete()")
+	}
+}
+
+func TestEncryptedFileStoreWithoutTerminal(t *testing.T) {
+	setTempHome(t)
+	t.Setenv(EnvPassphrase, "")
+
+	// No prompt is set when stdin isn't a terminal
+	err := encryptedFileStore{DefaultProfile}.Set("secret-token")
+	if err == nil || !strings.Contains(err.Error(), EnvPassphrase) {
+		t.Errorf("Set() without a prompt error = %v, want a pointer to %s", err, EnvPassphrase)
+	}
+}
+
+func TestEncryptedFileStorePassphraseCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(EnvPassphrase, "")
+
+	var prompts []bool
+	PassphrasePrompt = func(confirm bool) (string, error) {
+		prompts = append(prompts, confirm)
+		return "correct horse", nil
+	}
+	defer func() { PassphrasePrompt = nil }()
+
+	store := encryptedFileStore{"cache-test"}
+	if err := store.Set("first-token"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	forgetPassphrase(store.profile) // As in a new process
+
+	for i := 0; i < 2; i++ {
+		if got, err := store.Get(); err != nil || got != "first-token" {
+			t.Fatalf("Get() = %q, %v, want first-token", got, err)
+		}
+	}
+	// A token refresh re-encrypts under the same passphrase
+	if err := store.Set("refreshed-token"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got, err := store.Get(); err != nil || got != "refreshed-token" {
+		t.Errorf("Get() = %q, %v, want refreshed-token", got, err)
+	}
+
+	// One confirmed prompt to choose the passphrase, then one to unlock
+	if len(prompts) != 2 || !prompts[0] || prompts[1] {
+		t.Errorf("prompts = %v, want [true false]", prompts)
+	}
+
+	if err := store.Delete(); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+}
+
+func TestHasStoredCredentials(t *testing.T) {
+	setTempHome(t)
+	t.Setenv(EnvCredentialStore, StoreEncryptedFile)
+	t.Setenv(EnvAuthToken, "env-token")
+
+	if HasStoredCredentials() {
+		t.Error("HasStoredCredentials() = true with only LANE_TOKEN set")
+	}
+
+	t.Setenv(EnvPassphrase, "correct horse")
+	if err := SaveAuthToken("stored-token"); err != nil {
+		t.Fatalf("SaveAuthToken() error = %v", err)
+	}
+	forgetPassphrase(DefaultProfile)
+
+	t.Setenv(EnvPassphrase, "")
+	PassphrasePrompt = func(confirm bool) (string, error) {
+		t.Error("HasStoredCredentials() asked for the passphrase")
+		return "", errors.New("no prompt")
+	}
+	defer func() { PassphrasePrompt = nil }()
+	if !HasStoredCredentials() {
+		t.Error("HasStoredCredentials() = false with a token file")
+	}
+}
+
+func TestMigrateLegacyToken(t *testing.T) {
+	mockKeyring()
+
+	home := setTempHome(t)
+
+	t.Setenv(EnvAuthToken, "")
+	t.Setenv(EnvCredentialStore, StoreKeyring)
+
+	if err := (fileStore{DefaultProfile}).Set("legacy-token"); err != nil {
+		t.Fatalf("failed to write legacy token: %v", err)
+	}
+
+	got, err := GetAuthToken()
+	if err != nil || got != "legacy-token" {
+		t.Fatalf("GetAuthToken() = %q, %v, want legacy-token", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".lane", "token")); !os.IsNotExist(err) {
+		t.Error("plaintext token was not removed after migration")
+	}
+	if got, _ := (keyringStore{DefaultProfile}).Get(); got != "legacy-token" {
+		t.Errorf("keyring token = %q, want legacy-token", got)
+	}
+
+	if err := DeleteAuthToken(); err != nil {
+		t.Fatalf("DeleteAuthToken() error = %v", err)
+	}
+	if IsLoggedIn() {
+		t.Error("IsLoggedIn() = true after DeleteAuthToken()")
+	}
+}
+
+func TestEncodeCredentials(t *testing.T) {
+	if got := encodeCredentials(Credentials{Token: "plain"}); got != "plain" {
+		t.Errorf("encodeCredentials() of a plain token = %q, want it unchanged", got)
+	}
+
+	expires := time.Date(2026, 11, 30, 12, 0, 0, 0, time.UTC)
+	creds := Credentials{Token: "access", RefreshToken: "refresh", ExpiresAt: &expires}
+	got := decodeCredentials(encodeCredentials(creds))
+	if got.Token != "access" || got.RefreshToken != "refresh" || got.ExpiresAt == nil || !got.ExpiresAt.Equal(expires) {
+		t.Errorf("round trip = %+v, want %+v", got, creds)
+	}
+	if !got.CanRefresh() {
+		t.Error("CanRefresh() = false with a refresh token")
+	}
+
+	if got := decodeCredentials("{not json"); got.Token != "{not json" {
+		t.Errorf("decodeCredentials() of an unparseable value = %+v, want it as the token", got)
+	}
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EnvCredentialHelper = "LANE_CREDENTIAL_HELPER" // External command that stores the token
+
+	// StoreHelper is the name of the external credential helper store
+	StoreHelper = "helper"
+)
+
+// GetCredentialHelper returns the external credential helper command, if any
+func GetCredentialHelper() string {
+	return strings.TrimSpace(getSetting("credential.helper"))
+}
+
+// helperStore hands the token to an external program, in the style of git
+// credential helpers. The helper is run through the shell with the verb
+// (get, store or erase) appended as its last argument; a leading "!" is
+// dropped, so commands copied from a git config work too. It is given
+// key=value lines on stdin ending with a blank line:
+//
+//	protocol=https
+//	host=lane-website.netlify.app
+//	profile=work                 (profiles other than default)
+//	token=...                    (store only)
+//	oauth_refresh_token=...      (store only, when the login has one)
+//	password_expiry_utc=...      (store only, Unix time the token expires)
+//
+// For get, the helper prints the same lines back: a "token=" line (a
+// "password=" line is accepted too, so existing git helpers work) and
+// optionally the refresh token and expiry. Output that is a single line of
+// anything else is taken as the bare token, so commands like "pass show"
+// work as they are. Printing nothing means no token is stored. A non-zero
+// exit is an error.
+type helperStore struct {
+	command string
+	profile string
+}
+
+func (helperStore) Name() string { return StoreHelper }
+
+func (h helperStore) Get() (string, error) {
+	out, err := h.run("get", Credentials{})
+	if err != nil {
+		return "", err
+	}
+
+	creds, err := parseHelperCredentials(out)
+	if err != nil {
+		return "", err
+	}
+	if creds.Token == "" {
+		return "", ErrNoCredential
+	}
+	return encodeCredentials(creds), nil
+}
+
+func (h helperStore) Set(value string) error {
+	_, err := h.run("store", decodeCredentials(value))
+	return err
+}
+
+func (h helperStore) Delete() error {
+	_, err := h.run("erase", Credentials{})
+	return err
+}
+
+// run invokes the helper with a verb and returns its stdout
+func (h helperStore) run(verb string, creds Credentials) ([]byte, error) {
+	var input bytes.Buffer
+	input.WriteString("protocol=https\n")
+	if host := helperHost(); host != "" {
+		fmt.Fprintf(&input, "host=%s\n", host)
+	}
+	if h.profile != "" && h.profile != DefaultProfile {
+		fmt.Fprintf(&input, "profile=%s\n", h.profile)
+	}
+	if creds.Token != "" {
+		fmt.Fprintf(&input, "token=%s\n", creds.Token)
+	}
+	if creds.RefreshToken != "" {
+		fmt.Fprintf(&input, "oauth_refresh_token=%s\n", creds.RefreshToken)
+	}
+	if creds.ExpiresAt != nil {
+		fmt.Fprintf(&input, "password_expiry_utc=%d\n", creds.ExpiresAt.Unix())
+	}
+	input.WriteString("\n")
+
+	command := strings.TrimPrefix(h.command, "!") + " " + verb
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = &input
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("credential helper %s failed: %s", verb, msg)
+		}
+		return nil, fmt.Errorf("credential helper %s failed: %w", verb, err)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// helperHost returns the API host passed to the helper
+func helperHost() string {
+	u, err := url.Parse(GetAPIURL())
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// helperKeys are the attributes a helper may print for get. A single line
+// that doesn't start with one of them is a bare token.
+var helperKeys = []string{"token", "password", "oauth_refresh_token", "password_expiry_utc", "protocol", "host", "profile", "username"}
+
+// parseHelperCredentials reads the login printed by a helper's get
+func parseHelperCredentials(out []byte) (Credentials, error) {
+	text := strings.TrimRight(string(out), "\r\n")
+	if text != "" && !strings.ContainsAny(text, "\r\n") && !hasHelperKey(text) {
+		return Credentials{Token: text}, nil
+	}
+
+	attrs := parseHelperOutput(out)
+	creds := Credentials{Token: attrs["token"], RefreshToken: attrs["oauth_refresh_token"]}
+	if creds.Token == "" {
+		creds.Token = attrs["password"]
+	}
+	if expiry := attrs["password_expiry_utc"]; expiry != "" {
+		secs, err := strconv.ParseInt(expiry, 10, 64)
+		if err != nil {
+			return Credentials{}, fmt.Errorf("credential helper get: invalid password_expiry_utc %q", expiry)
+		}
+		expiresAt := time.Unix(secs, 0).UTC()
+		creds.ExpiresAt = &expiresAt
+	}
+	return creds, nil
+}
+
+// hasHelperKey reports whether line is one of the helper's key=value lines
+func hasHelperKey(line string) bool {
+	key, _, ok := strings.Cut(line, "=")
+	if !ok {
+		return false
+	}
+	for _, k := range helperKeys {
+		if key == k {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHelperOutput reads key=value lines up to the first blank line
+func parseHelperOutput(out []byte) map[string]string {
+	attrs := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			break
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			attrs[key] = value
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeHelper is a credential helper that keeps the token in a file and
+// records the input it was given for each verb
+const fakeHelper = `#!/bin/sh
+dir=$(dirname "$0")
+cat > "$dir/input-$1"
+case "$1" in
+get)   [ -f "$dir/secret" ] && cat "$dir/secret" ;;
+store) grep -E '^(token|oauth_refresh_token|password_expiry_utc)=' "$dir/input-store" > "$dir/secret" ;;
+erase) rm -f "$dir/secret" ;;
+esac
+exit 0
+`
+
+func TestHelperStore(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("helper script requires sh")
+	}
+
+	tmpDir := t.TempDir()
+
+	script := filepath.Join(tmpDir, "helper.sh")
+	if err := os.WriteFile(script, []byte(fakeHelper), 0700); err != nil {
+		t.Fatalf("failed to write helper: %v", err)
+	}
+
+	t.Setenv(EnvAPIURL, "https://lane.example.com")
+	t.Setenv(EnvCredentialHelper, script)
+
+	store, err := GetCredentialStore()
+	if err != nil {
+		t.Fatalf("GetCredentialStore() error = %v", err)
+	}
+	if store.Name() != StoreHelper {
+		t.Fatalf("GetCredentialStore() = %s, want helper", store.Name())
+	}
+
+	if _, err := store.Get(); !errors.Is(err, ErrNoCredential) {
+		t.Fatalf("Get() error = %v, want ErrNoCredential", err)
+	}
+
+	if err := store.Set("helper-token"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	input, _ := os.ReadFile(filepath.Join(tmpDir, "input-store"))
+	want := "protocol=https\nhost=lane.example.com\ntoken=helper-token\n\n"
+	if string(input) != want {
+		t.Errorf("store input = %q, want %q", input, want)
+	}
+
+	if got, err := store.Get(); err != nil || got != "helper-token" {
+		t.Errorf("Get() = %q, %v, want helper-token", got, err)
+	}
+
+	expiresAt := time.Unix(1900000000, 0).UTC()
+	creds := Credentials{Token: "access", RefreshToken: "refresh", ExpiresAt: &expiresAt}
+	if err := store.Set(encodeCredentials(creds)); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	input, _ = os.ReadFile(filepath.Join(tmpDir, "input-store"))
+	want = "protocol=https\nhost=lane.example.com\ntoken=access\noauth_refresh_token=refresh\npassword_expiry_utc=1900000000\n\n"
+	if string(input) != want {
+		t.Errorf("store input = %q, want %q", input, want)
+	}
+	value, err := store.Get()
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := decodeCredentials(value); got.Token != "access" || got.RefreshToken != "refresh" || got.ExpiresAt == nil || !got.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("Get() = %+v, want the refresh token and expiry back", got)
+	}
+
+	if err := store.Delete(); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Get(); !errors.Is(err, ErrNoCredential) {
+		t.Errorf("Get() after Delete() error = %v, want ErrNoCredential", err)
+	}
+}
+
+func TestHelperStoreFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("helper script requires sh")
+	}
+
+	store := helperStore{command: "echo 'vault is locked' >&2; exit 1; true"}
+	_, err := store.Get()
+	if err == nil || !strings.Contains(err.Error(), "vault is locked") {
+		t.Errorf("Get() error = %v, want helper stderr", err)
+	}
+}
+
+func TestHelperStoreBareOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("helper script requires sh")
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		want    string
+	}{
+		{"bare token", `f() { [ "$1" = get ] && echo lane_abc=; }; f`, "lane_abc="},
+		{"git style", `!f() { [ "$1" = get ] && echo password=lane_abc; }; f`, "lane_abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := helperStore{command: tt.command}
+			if got, err := store.Get(); err != nil || got != tt.want {
+				t.Errorf("Get() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHelperCredentials(t *testing.T) {
+	tests := []struct {
+		name    string
+		out     string
+		want    string
+		wantErr bool
+	}{
+		{"empty", "", "", false},
+		{"bare token", "lane_abc\n", "lane_abc", false},
+		{"token line", "token=lane_abc\n", "lane_abc", false},
+		{"username only", "username=lane\n", "", false},
+		{"several bare lines", "lane_abc\nmore\n", "", false},
+		{"bad expiry", "token=lane_abc\npassword_expiry_utc=soon\n", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseHelperCredentials([]byte(tt.out))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseHelperCredentials() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Token != tt.want {
+				t.Errorf("parseHelperCredentials() token = %q, want %q", got.Token, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHelperOutput(t *testing.T) {
+	attrs := parseHelperOutput([]byte("username=lane\r\npassword=abc=def\n\ntoken=ignored\n"))
+	if attrs["username"] != "lane" || attrs["password"] != "abc=def" {
+		t.Errorf("parseHelperOutput() = %v", attrs)
+	}
+	if _, ok := attrs["token"]; ok {
+		t.Error("parseHelperOutput() read past the blank line")
+	}
+}
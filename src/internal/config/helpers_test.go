@@ -0,0 +1,25 @@
+package config
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+// setTempHome points HOME at an empty temp directory for the test
+func setTempHome(t *testing.T) string {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(EnvXDGConfigHome, "")
+	return home
+}
+
+// mockKeyring swaps the OS keyring for an in-memory one and forgets whether
+// the real one was reachable
+func mockKeyring() {
+	keyring.MockInit()
+	keyringAvailable = sync.OnceValue(probeKeyring)
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Debug log rotation
+const (
+	logFile     = "lane.log"
+	maxLogSize  = 1 << 20 // Rotate once the log passes 1 MiB
+	maxLogFiles = 3       // Rotated logs kept, lane.log.1 (newest) to lane.log.3
+)
+
+// LogPath returns the path of the debug log written with --log-file
+func LogPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "logs", logFile), nil
+}
+
+// OpenLog opens the debug log for appending, rotating it first if it has
+// grown past maxLogSize
+func OpenLog() (*os.File, error) {
+	path, err := LogPath()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("could not create log directory: %w", err)
+	}
+
+	if info, err := os.Stat(path); err == nil && info.Size() >= maxLogSize {
+		if err := rotateLog(path); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open log file: %w", err)
+	}
+	return f, nil
+}
+
+// rotateLog shifts path to path.1, path.1 to path.2 and so on, dropping
+// the oldest
+func rotateLog(path string) error {
+	for i := maxLogFiles - 1; i >= 1; i-- {
+		older := fmt.Sprintf("%s.%d", path, i)
+		if _, err := os.Stat(older); err == nil {
+			if err := os.Rename(older, fmt.Sprintf("%s.%d", path, i+1)); err != nil {
+				return fmt.Errorf("could not rotate log file: %w", err)
+			}
+		}
+	}
+	if err := os.Rename(path, path+".1"); err != nil {
+		return fmt.Errorf("could not rotate log file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenLog(t *testing.T) {
+	home := setTempHome(t)
+
+	path, err := LogPath()
+	if err != nil {
+		t.Fatalf("LogPath() error = %v", err)
+	}
+	if want := filepath.Join(home, ".lane", "logs", "lane.log"); path != want {
+		t.Errorf("LogPath() = %q, want %q", path, want)
+	}
+
+	write := func(data []byte) {
+		t.Helper()
+		f, err := OpenLog()
+		if err != nil {
+			t.Fatalf("OpenLog() error = %v", err)
+		}
+		defer f.Close()
+		if _, err := f.Write(data); err != nil {
+			t.Fatalf("failed to write log: %v", err)
+		}
+	}
+
+	t.Run("appends while small", func(t *testing.T) {
+		write([]byte("one\n"))
+		write([]byte("two\n"))
+
+		data, _ := os.ReadFile(path)
+		if string(data) != "one\ntwo\n" {
+			t.Errorf("log = %q, want both writes", data)
+		}
+		if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+			t.Error("log rotated before reaching the size limit")
+		}
+	})
+
+	t.Run("rotates past the size limit", func(t *testing.T) {
+		for i := 1; i <= maxLogFiles+1; i++ {
+			os.WriteFile(path, make([]byte, maxLogSize), 0600)
+			write([]byte(fmt.Sprintf("run %d\n", i)))
+		}
+
+		data, _ := os.ReadFile(path)
+		if string(data) != fmt.Sprintf("run %d\n", maxLogFiles+1) {
+			t.Errorf("log = %q, want only the last run", data)
+		}
+		for i := 1; i <= maxLogFiles; i++ {
+			if _, err := os.Stat(fmt.Sprintf("%s.%d", path, i)); err != nil {
+				t.Errorf("rotated log %d missing: %v", i, err)
+			}
+		}
+		if _, err := os.Stat(fmt.Sprintf("%s.%d", path, maxLogFiles+1)); !os.IsNotExist(err) {
+			t.Errorf("kept more than %d rotated logs", maxLogFiles)
+		}
+	})
+}
@@ -0,0 +1,260 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	EnvProfile     = "LANE_PROFILE" // Profile to use instead of the current one
+	DefaultProfile = "default"
+
+	profilesFile = "profiles.json"
+)
+
+// Profile is a named account with its own token, API URL, default currency
+// and client book. The default profile keeps its files directly in
+// ~/.lane; others live in ~/.lane/profiles/<name>.
+type Profile struct {
+	Name     string `json:"-"`
+	APIURL   string `json:"api_url,omitempty"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// profileSettings is the on-disk format of profiles.json
+type profileSettings struct {
+	Current  string             `json:"current,omitempty"`
+	Profiles map[string]Profile `json:"profiles"`
+}
+
+// profileOverride is the profile picked with --profile, if any
+var profileOverride string
+
+// SetProfile selects the profile for this run, taking precedence over
+// LANE_PROFILE and the current profile
+func SetProfile(name string) {
+	profileOverride = strings.ToLower(strings.TrimSpace(name))
+}
+
+// GetProfile returns the active profile name: --profile, then LANE_PROFILE,
+// then the profile chosen with 'lane profiles use', then "default"
+func GetProfile() string {
+	if profileOverride != "" {
+		return profileOverride
+	}
+	if name := strings.TrimSpace(os.Getenv(EnvProfile)); name != "" {
+		return strings.ToLower(name)
+	}
+	if settings, err := loadProfileSettings(); err == nil && settings.Current != "" {
+		return settings.Current
+	}
+	return DefaultProfile
+}
+
+// GetActiveProfile returns the settings of the active profile. A profile
+// that was never saved has no settings.
+func GetActiveProfile() Profile {
+	name := GetProfile()
+	settings, err := loadProfileSettings()
+	if err != nil {
+		return Profile{Name: name}
+	}
+	p := settings.Profiles[name]
+	p.Name = name
+	return p
+}
+
+// ValidateProfileName checks that a profile name is usable as a directory
+func ValidateProfileName(name string) error {
+	if err := ValidateAlias(name); err != nil {
+		return fmt.Errorf("invalid profile name %q (use lowercase letters, digits, '-' and '_')", name)
+	}
+	return nil
+}
+
+// ProfileExists reports whether a profile has been saved. The default
+// profile always exists.
+func ProfileExists(name string) (bool, error) {
+	if name == DefaultProfile {
+		return true, nil
+	}
+	settings, err := loadProfileSettings()
+	if err != nil {
+		return false, err
+	}
+	_, ok := settings.Profiles[name]
+	return ok, nil
+}
+
+// ListProfiles returns all profiles sorted by name, always including the
+// default profile
+func ListProfiles() ([]Profile, error) {
+	settings, err := loadProfileSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := settings.Profiles[DefaultProfile]; !ok {
+		settings.Profiles[DefaultProfile] = Profile{}
+	}
+
+	profiles := make([]Profile, 0, len(settings.Profiles))
+	for name, p := range settings.Profiles {
+		p.Name = name
+		profiles = append(profiles, p)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+	return profiles, nil
+}
+
+// SaveProfile creates or updates a profile
+func SaveProfile(p Profile) error {
+	if err := ValidateProfileName(p.Name); err != nil {
+		return err
+	}
+
+	settings, err := loadProfileSettings()
+	if err != nil {
+		return err
+	}
+	settings.Profiles[p.Name] = p
+	return saveProfileSettings(settings)
+}
+
+// UseProfile makes a profile the current one
+func UseProfile(name string) error {
+	settings, err := loadProfileSettings()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := settings.Profiles[name]; !ok && name != DefaultProfile {
+		return fmt.Errorf("no profile named %q (see 'lane profiles list')", name)
+	}
+
+	settings.Current = name
+	if name == DefaultProfile {
+		settings.Current = ""
+	}
+	return saveProfileSettings(settings)
+}
+
+// RemoveProfile deletes a profile along with its token and client book.
+// The default profile cannot be removed.
+func RemoveProfile(name string) error {
+	if name == DefaultProfile {
+		return fmt.Errorf("the default profile cannot be removed")
+	}
+
+	settings, err := loadProfileSettings()
+	if err != nil {
+		return err
+	}
+	if _, ok := settings.Profiles[name]; !ok {
+		return fmt.Errorf("no profile named %q (see 'lane profiles list')", name)
+	}
+
+	store, err := credentialStoreFor(name)
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(); err != nil {
+		return err
+	}
+
+	dir, err := profileDirFor(name)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("could not delete profile directory: %w", err)
+	}
+
+	delete(settings.Profiles, name)
+	if settings.Current == name {
+		settings.Current = ""
+	}
+	return saveProfileSettings(settings)
+}
+
+// profileDir returns the directory holding the active profile's files
+func profileDir() (string, error) {
+	return profileDirFor(GetProfile())
+}
+
+// profileDirFor returns the directory holding a profile's files
+func profileDirFor(name string) (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	if name == DefaultProfile {
+		return dir, nil
+	}
+	if err := ValidateProfileName(name); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "profiles", name), nil
+}
+
+// loadProfileSettings reads profiles.json, returning empty settings if it
+// doesn't exist yet
+func loadProfileSettings() (profileSettings, error) {
+	settings := profileSettings{Profiles: map[string]Profile{}}
+
+	dir, err := configDir()
+	if err != nil {
+		return settings, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, profilesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil
+		}
+		return settings, fmt.Errorf("could not read profiles: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return settings, fmt.Errorf("could not parse %s: %w", profilesFile, err)
+	}
+	if settings.Profiles == nil {
+		settings.Profiles = map[string]Profile{}
+	}
+	return settings, nil
+}
+
+// saveProfileSettings writes profiles.json
+func saveProfileSettings(settings profileSettings) error {
+	dir, err := configDir()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("could not create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode profiles: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, profilesFile), data, 0600); err != nil {
+		return fmt.Errorf("could not save profiles: %w", err)
+	}
+	invalidateSettings()
+	return nil
+}
+
+// GetProfileDir returns the active profile's directory (for display)
+func GetProfileDir() string {
+	dir, _ := profileDir()
+	return dir
+}
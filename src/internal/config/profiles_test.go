@@ -0,0 +1,121 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	home := setTempHome(t)
+
+	t.Setenv(EnvProfile, "")
+	t.Setenv(EnvCredentialStore, StoreFile)
+	t.Setenv(EnvAuthToken, "")
+
+	defer SetProfile("")
+
+	t.Run("default profile", func(t *testing.T) {
+		if got := GetProfile(); got != DefaultProfile {
+			t.Errorf("GetProfile() = %q, want %q", got, DefaultProfile)
+		}
+		if got := GetDefaultCurrency(); got != DefaultCurrency {
+			t.Errorf("GetDefaultCurrency() = %q, want %q", got, DefaultCurrency)
+		}
+		if exists, _ := ProfileExists("work"); exists {
+			t.Error("ProfileExists(work) = true before it was saved")
+		}
+	})
+
+	t.Run("profiles keep separate tokens and address books", func(t *testing.T) {
+		if err := SaveAuthToken("default-token"); err != nil {
+			t.Fatalf("SaveAuthToken() error = %v", err)
+		}
+		if err := AddClient(Client{Alias: "acme", Name: "Acme"}); err != nil {
+			t.Fatalf("AddClient() error = %v", err)
+		}
+
+		if err := SaveProfile(Profile{Name: "work", APIURL: "https://work.example.com", Currency: "eur"}); err != nil {
+			t.Fatalf("SaveProfile() error = %v", err)
+		}
+		SetProfile("work")
+
+		if IsLoggedIn() {
+			t.Error("IsLoggedIn() = true for a new profile")
+		}
+		if clients, _ := LoadClients(); len(clients) != 0 {
+			t.Errorf("LoadClients() = %v, want an empty address book", clients)
+		}
+		if err := SaveAuthToken("work-token"); err != nil {
+			t.Fatalf("SaveAuthToken() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(home, ".lane", "profiles", "work", "token")); err != nil {
+			t.Errorf("work token not saved in the profile directory: %v", err)
+		}
+		if got := GetAPIURL(); got != "https://work.example.com" {
+			t.Errorf("GetAPIURL() = %q, want the profile's URL", got)
+		}
+		if got := GetDefaultCurrency(); got != "eur" {
+			t.Errorf("GetDefaultCurrency() = %q, want eur", got)
+		}
+
+		SetProfile("")
+		if got, _ := GetAuthToken(); got != "default-token" {
+			t.Errorf("GetAuthToken() = %q, want default-token", got)
+		}
+	})
+
+	t.Run("LANE_PROFILE and use", func(t *testing.T) {
+		if err := UseProfile("work"); err != nil {
+			t.Fatalf("UseProfile() error = %v", err)
+		}
+		if got := GetProfile(); got != "work" {
+			t.Errorf("GetProfile() = %q, want work", got)
+		}
+
+		t.Setenv(EnvProfile, "default")
+		if got := GetProfile(); got != DefaultProfile {
+			t.Errorf("GetProfile() with %s = %q, want default", EnvProfile, got)
+		}
+		t.Setenv(EnvProfile, "")
+
+		SetProfile("default")
+		if got := GetProfile(); got != DefaultProfile {
+			t.Errorf("GetProfile() with --profile = %q, want default", got)
+		}
+		SetProfile("")
+
+		if err := UseProfile("missing"); err == nil {
+			t.Error("UseProfile() should reject unknown profiles")
+		}
+	})
+
+	t.Run("list and remove", func(t *testing.T) {
+		profiles, err := ListProfiles()
+		if err != nil {
+			t.Fatalf("ListProfiles() error = %v", err)
+		}
+		if len(profiles) != 2 || profiles[0].Name != "default" || profiles[1].Name != "work" {
+			t.Errorf("ListProfiles() = %+v, want default and work", profiles)
+		}
+
+		if err := RemoveProfile(DefaultProfile); err == nil {
+			t.Error("RemoveProfile() should refuse the default profile")
+		}
+		if err := RemoveProfile("work"); err != nil {
+			t.Fatalf("RemoveProfile() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(home, ".lane", "profiles", "work")); !os.IsNotExist(err) {
+			t.Error("profile directory still exists after RemoveProfile()")
+		}
+		if got := GetProfile(); got != DefaultProfile {
+			t.Errorf("GetProfile() = %q after removing the current profile, want default", got)
+		}
+	})
+
+	t.Run("invalid names", func(t *testing.T) {
+		if err := SaveProfile(Profile{Name: "../etc"}); err == nil {
+			t.Error("SaveProfile() should reject names that aren't safe directory names")
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFile is the per-project defaults file, found by walking up from
+// the working directory
+const ProjectFile = ".lane.yaml"
+
+// Project holds the defaults from a .lane.yaml file
+type Project struct {
+	Path              string `yaml:"-"`
+	Client            string `yaml:"client"` // Client name or address book alias
+	Email             string `yaml:"email"`
+	Currency          string `yaml:"currency"`
+	Rate              string `yaml:"rate"`               // Hourly rate, used with --hours
+	DescriptionPrefix string `yaml:"description_prefix"` // Prepended to --desc
+}
+
+// FindProject looks for .lane.yaml in the working directory and each of its
+// parents. It returns nil if there is none.
+func FindProject() (*Project, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, nil
+	}
+
+	for {
+		p, err := readProject(filepath.Join(dir, ProjectFile))
+		if p != nil || err != nil {
+			return p, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// readProject parses a .lane.yaml file, returning nil if it doesn't exist
+func readProject(path string) (*Project, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
+
+	var p Project
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	p.Path = path
+
+	if p.Currency != "" {
+		s, _ := LookupSetting("currency")
+		if err := s.Validate(p.Currency); err != nil {
+			return nil, fmt.Errorf("%w in %s", err, path)
+		}
+	}
+	return &p, nil
+}
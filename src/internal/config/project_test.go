@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindProject(t *testing.T) {
+	home := setTempHome(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+
+	repo := filepath.Join(home, "acme-site")
+	nested := filepath.Join(repo, "src", "pages")
+	if err := os.MkdirAll(nested, 0700); err != nil {
+		t.Fatalf("failed to create dirs: %v", err)
+	}
+
+	t.Run("no project file", func(t *testing.T) {
+		os.Chdir(nested)
+		p, err := FindProject()
+		if err != nil || p != nil {
+			t.Errorf("FindProject() = %+v, %v, want nil", p, err)
+		}
+	})
+
+	path := filepath.Join(repo, ProjectFile)
+	data := "client: acme\nemail: ap@acme.com\ncurrency: eur\nrate: 120\ndescription_prefix: \"Acme site:\"\n"
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatalf("failed to write project file: %v", err)
+	}
+
+	t.Run("found from a subdirectory", func(t *testing.T) {
+		os.Chdir(nested)
+		p, err := FindProject()
+		if err != nil {
+			t.Fatalf("FindProject() error = %v", err)
+		}
+		if p == nil {
+			t.Fatal("FindProject() = nil, want the project file")
+		}
+		if got, _ := filepath.EvalSymlinks(p.Path); got != mustEvalSymlinks(t, path) {
+			t.Errorf("Path = %q, want %q", p.Path, path)
+		}
+		if p.Client != "acme" || p.Email != "ap@acme.com" || p.Rate != "120" || p.DescriptionPrefix != "Acme site:" {
+			t.Errorf("unexpected project: %+v", p)
+		}
+	})
+
+	t.Run("currency layer", func(t *testing.T) {
+		os.Chdir(nested)
+		v, err := GetSetting("currency")
+		if err != nil {
+			t.Fatalf("GetSetting() error = %v", err)
+		}
+		if v.Value != "eur" || filepath.Base(v.Origin) != ProjectFile {
+			t.Errorf("GetSetting(currency) = %+v, want eur from the project file", v)
+		}
+	})
+
+	t.Run("invalid files", func(t *testing.T) {
+		os.Chdir(repo)
+		for _, bad := range []string{"currency: xyz\n", "clinet: acme\n"} {
+			if err := os.WriteFile(path, []byte(bad), 0600); err != nil {
+				t.Fatalf("failed to write project file: %v", err)
+			}
+			if _, err := FindProject(); err == nil {
+				t.Errorf("FindProject() expected error for %q", bad)
+			}
+		}
+	})
+}
+
+// mustEvalSymlinks resolves path, which may sit under a symlinked temp dir
+func mustEvalSymlinks(t *testing.T, path string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatalf("EvalSymlinks(%s) error = %v", path, err)
+	}
+	return resolved
+}
@@ -0,0 +1,491 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/forrestcai35/lane/internal/currency"
+)
+
+const (
+	EnvXDGConfigHome = "XDG_CONFIG_HOME" // Moves config.toml to $XDG_CONFIG_HOME/lane
+
+	userConfigFile = "config.toml"
+)
+
+// Setting is a value that can be set in config.toml. Each setting resolves
+// from, highest precedence first: an env var, the project file, the
+// profile, the user's config.toml, then its default. Command-line flags
+// take precedence over all of these.
+type Setting struct {
+	Key         string
+	Env         string
+	Default     string
+	Description string
+	validate    func(string) error
+}
+
+// Settings lists every supported setting
+var Settings = []Setting{
+	{Key: "api_url", Env: EnvAPIURL, Default: DefaultAPIURL, Description: "Lane API URL", validate: validateURL},
+	{Key: "currency", Env: "LANE_CURRENCY", Default: DefaultCurrency, Description: "Default currency code", validate: validateCurrency},
+	{Key: "locale", Env: EnvLocale, Default: DefaultLocale, Description: "Number format for amounts (en, de, ...)"},
+	{Key: "send", Env: "LANE_SEND", Default: "false", Description: "Email new invoices by default", validate: validateBool},
+	{Key: "clipboard", Env: "LANE_CLIPBOARD", Default: "true", Description: "Copy payment links to the clipboard", validate: validateBool},
+	{Key: "output", Env: "LANE_OUTPUT", Default: "table", Description: "Output format for listings (table, json)", validate: oneOf("table", "json")},
+	{Key: "theme", Env: "LANE_THEME", Default: "auto", Description: "Colors (auto, dark, light, none)", validate: oneOf("auto", "dark", "light", "none")},
+	{Key: "timeout", Env: "LANE_TIMEOUT", Default: "30s", Description: "API request timeout", validate: validateDuration},
+	{Key: "debug", Env: EnvDebug, Default: "false", Description: "Log API requests to stderr", validate: validateBool},
+	{Key: "credential.helper", Env: EnvCredentialHelper, Description: "External command that stores the token"},
+	{Key: "credential.store", Env: EnvCredentialStore, Description: "Token store (keyring, encrypted-file, file)", validate: oneOf("auto", StoreKeyring, StoreEncryptedFile, StoreFile)},
+}
+
+// Value is a resolved setting and where it came from
+type Value struct {
+	Key    string
+	Value  string
+	Origin string // e.g. "default", "env LANE_CURRENCY", "profile work"
+}
+
+// layer is one source of settings, keyed by setting
+type layer map[string]Value
+
+// fileLayers caches the layers read from disk, so a command that reads
+// many settings parses config.toml, profiles.json and .lane.yaml once.
+// The cache is keyed by what picks those files, since tests and --profile
+// can change it within one process.
+var fileLayers struct {
+	sync.Mutex
+	key    layersKey
+	layers []layer
+	err    error
+	loaded bool
+}
+
+// layersKey is what decides which files loadLayers reads
+type layersKey struct {
+	configPath string
+	dir        string
+	profile    string
+	envProfile string
+}
+
+// invalidateSettings drops the cached layers after a settings file changes
+func invalidateSettings() {
+	fileLayers.Lock()
+	defer fileLayers.Unlock()
+	fileLayers.loaded = false
+	fileLayers.layers = nil
+	fileLayers.err = nil
+}
+
+// LookupSetting returns the setting with the given key
+func LookupSetting(key string) (Setting, bool) {
+	for _, s := range Settings {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return Setting{}, false
+}
+
+// Validate checks a value for the setting
+func (s Setting) Validate(value string) error {
+	if s.validate == nil || value == "" {
+		return nil
+	}
+	if err := s.validate(value); err != nil {
+		return fmt.Errorf("invalid %s %q: %w", s.Key, value, err)
+	}
+	return nil
+}
+
+// UserConfigPath returns the path of config.toml: $XDG_CONFIG_HOME/lane
+// when XDG_CONFIG_HOME is set, otherwise ~/.lane
+func UserConfigPath() (string, error) {
+	if xdg := os.Getenv(EnvXDGConfigHome); xdg != "" {
+		return filepath.Join(xdg, "lane", userConfigFile), nil
+	}
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, userConfigFile), nil
+}
+
+// GetSetting resolves a setting
+func GetSetting(key string) (Value, error) {
+	if _, ok := LookupSetting(key); !ok {
+		return Value{}, fmt.Errorf("unknown setting %q (see 'lane config list')", key)
+	}
+
+	layers, err := loadLayers()
+	if err != nil {
+		return Value{}, err
+	}
+	return resolve(key, layers), nil
+}
+
+// ListSettings resolves every setting
+func ListSettings() ([]Value, error) {
+	layers, err := loadLayers()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]Value, 0, len(Settings))
+	for _, s := range Settings {
+		values = append(values, resolve(s.Key, layers))
+	}
+	return values, nil
+}
+
+// CheckSettings reports a config file that can't be read or has invalid
+// values, so mistakes surface before any command runs
+func CheckSettings() error {
+	_, err := loadLayers()
+	return err
+}
+
+// SetUserSetting saves a setting in the user's config.toml
+func SetUserSetting(key, value string) error {
+	s, ok := LookupSetting(key)
+	if !ok {
+		return fmt.Errorf("unknown setting %q (see 'lane config list')", key)
+	}
+	if err := s.Validate(value); err != nil {
+		return err
+	}
+
+	raw, err := readUserConfig()
+	if err != nil {
+		return err
+	}
+
+	var typed any = value
+	if isBoolSetting(s) {
+		typed, _ = strconv.ParseBool(value)
+	}
+	setNested(raw, strings.Split(key, "."), typed)
+	if err := writeUserConfig(raw); err != nil {
+		return err
+	}
+	invalidateSettings()
+	return nil
+}
+
+// UnsetUserSetting removes a setting from the user's config.toml
+func UnsetUserSetting(key string) error {
+	if _, ok := LookupSetting(key); !ok {
+		return fmt.Errorf("unknown setting %q (see 'lane config list')", key)
+	}
+
+	raw, err := readUserConfig()
+	if err != nil {
+		return err
+	}
+	deleteNested(raw, strings.Split(key, "."))
+	if err := writeUserConfig(raw); err != nil {
+		return err
+	}
+	invalidateSettings()
+	return nil
+}
+
+// EnsureUserConfig creates an empty config.toml if there isn't one yet and
+// returns its path
+func EnsureUserConfig() (string, error) {
+	path, err := UserConfigPath()
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", fmt.Errorf("could not create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		return "", fmt.Errorf("could not create config: %w", err)
+	}
+	return path, nil
+}
+
+// getSetting resolves a setting, falling back to its default if the config
+// file is broken (CheckSettings reports that)
+func getSetting(key string) string {
+	v, err := GetSetting(key)
+	if err != nil {
+		s, _ := LookupSetting(key)
+		return s.Default
+	}
+	return v.Value
+}
+
+// resolve picks the highest-precedence value for a key
+func resolve(key string, layers []layer) Value {
+	for i := len(layers) - 1; i >= 0; i-- {
+		if v, ok := layers[i][key]; ok && v.Value != "" {
+			return v
+		}
+	}
+	s, _ := LookupSetting(key)
+	return Value{Key: key, Value: s.Default, Origin: "default"}
+}
+
+// loadLayers returns every source of settings, lowest precedence first.
+// Files are read once and cached; env vars are checked on every call.
+func loadLayers() ([]layer, error) {
+	files, err := cachedFileLayers()
+	if err != nil {
+		return nil, err
+	}
+
+	env := layer{}
+	for _, s := range Settings {
+		v := strings.TrimSpace(os.Getenv(s.Env))
+		if v == "" {
+			continue
+		}
+		if err := s.Validate(v); err != nil {
+			return nil, fmt.Errorf("%w in env %s", err, s.Env)
+		}
+		env[s.Key] = Value{Key: s.Key, Value: v, Origin: "env " + s.Env}
+	}
+
+	layers := make([]layer, 0, len(files)+1)
+	layers = append(layers, files...)
+	return append(layers, env), nil
+}
+
+// cachedFileLayers returns the file layers, reading them only when the
+// config path, working directory or profile selection has changed
+func cachedFileLayers() ([]layer, error) {
+	path, err := UserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	dir, _ := os.Getwd()
+	key := layersKey{
+		configPath: path,
+		dir:        dir,
+		profile:    profileOverride,
+		envProfile: os.Getenv(EnvProfile),
+	}
+
+	fileLayers.Lock()
+	defer fileLayers.Unlock()
+	if !fileLayers.loaded || fileLayers.key != key {
+		fileLayers.layers, fileLayers.err = readFileLayers(path)
+		fileLayers.key = key
+		fileLayers.loaded = true
+	}
+	return fileLayers.layers, fileLayers.err
+}
+
+// readFileLayers reads the user config, the active profile and the
+// project file
+func readFileLayers(path string) ([]layer, error) {
+	var layers []layer
+
+	raw, err := readUserConfig()
+	if err != nil {
+		return nil, err
+	}
+	user, err := flattenSettings(raw, path)
+	if err != nil {
+		return nil, err
+	}
+	layers = append(layers, user)
+
+	p := GetActiveProfile()
+	origin := "profile " + p.Name
+	layers = append(layers, layer{
+		"api_url":  {Key: "api_url", Value: p.APIURL, Origin: origin},
+		"currency": {Key: "currency", Value: p.Currency, Origin: origin},
+	})
+
+	project, err := FindProject()
+	if err != nil {
+		return nil, err
+	}
+	if project != nil {
+		layers = append(layers, layer{
+			"currency": {Key: "currency", Value: project.Currency, Origin: project.Path},
+		})
+	}
+	return layers, nil
+}
+
+// flattenSettings turns a parsed TOML document into a layer of dotted
+// keys, checking each key and value
+func flattenSettings(raw map[string]any, path string) (layer, error) {
+	values := layer{}
+
+	var walk func(prefix string, m map[string]any) error
+	walk = func(prefix string, m map[string]any) error {
+		for k, v := range m {
+			key := prefix + k
+			if nested, ok := v.(map[string]any); ok {
+				if err := walk(key+".", nested); err != nil {
+					return err
+				}
+				continue
+			}
+
+			s, ok := LookupSetting(key)
+			if !ok {
+				return fmt.Errorf("unknown setting %q in %s", key, path)
+			}
+			value := fmt.Sprint(v)
+			if err := s.Validate(value); err != nil {
+				return fmt.Errorf("%w in %s", err, path)
+			}
+			values[key] = Value{Key: key, Value: value, Origin: path}
+		}
+		return nil
+	}
+
+	if err := walk("", raw); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// readUserConfig parses config.toml, returning an empty document if it
+// doesn't exist yet
+func readUserConfig() (map[string]any, error) {
+	path, err := UserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	raw := map[string]any{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return raw, nil
+		}
+		return nil, fmt.Errorf("could not read config: %w", err)
+	}
+
+	if _, err := toml.Decode(string(data), &raw); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	return raw, nil
+}
+
+// writeUserConfig saves config.toml
+func writeUserConfig(raw map[string]any) error {
+	path, err := UserConfigPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("could not create config directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(raw); err != nil {
+		return fmt.Errorf("could not encode config: %w", err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("could not save config: %w", err)
+	}
+	return nil
+}
+
+// setNested sets a dotted key in a TOML document
+func setNested(m map[string]any, path []string, value any) {
+	for _, k := range path[:len(path)-1] {
+		next, ok := m[k].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			m[k] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = value
+}
+
+// deleteNested removes a dotted key, dropping tables left empty
+func deleteNested(m map[string]any, path []string) {
+	if len(path) == 1 {
+		delete(m, path[0])
+		return
+	}
+	next, ok := m[path[0]].(map[string]any)
+	if !ok {
+		return
+	}
+	deleteNested(next, path[1:])
+	if len(next) == 0 {
+		delete(m, path[0])
+	}
+}
+
+// SettingKeys returns every setting key, sorted
+func SettingKeys() []string {
+	keys := make([]string, 0, len(Settings))
+	for _, s := range Settings {
+		keys = append(keys, s.Key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isBoolSetting(s Setting) bool {
+	return s.Default == "true" || s.Default == "false"
+}
+
+func validateBool(v string) error {
+	if _, err := strconv.ParseBool(v); err != nil {
+		return fmt.Errorf("expected true or false")
+	}
+	return nil
+}
+
+func validateDuration(v string) error {
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("expected a duration such as 30s or 2m")
+	}
+	return nil
+}
+
+func validateURL(v string) error {
+	if !strings.HasPrefix(v, "http://") && !strings.HasPrefix(v, "https://") {
+		return fmt.Errorf("expected an http:// or https:// URL")
+	}
+	return nil
+}
+
+func validateCurrency(v string) error {
+	if _, ok := currency.Lookup(v); !ok {
+		return fmt.Errorf("unknown currency")
+	}
+	return nil
+}
+
+// oneOf accepts only the given values
+func oneOf(choices ...string) func(string) error {
+	return func(v string) error {
+		for _, c := range choices {
+			if strings.EqualFold(v, c) {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected one of %s", strings.Join(choices, ", "))
+	}
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSettings(t *testing.T) {
+	home := setTempHome(t)
+
+	for _, env := range []string{EnvProfile, EnvAPIURL, "LANE_CURRENCY", "LANE_SEND", "LANE_TIMEOUT"} {
+		t.Setenv(env, "")
+	}
+
+	defer SetProfile("")
+	path := filepath.Join(home, ".lane", userConfigFile)
+
+	t.Run("defaults", func(t *testing.T) {
+		v, err := GetSetting("currency")
+		if err != nil {
+			t.Fatalf("GetSetting() error = %v", err)
+		}
+		if v.Value != DefaultCurrency || v.Origin != "default" {
+			t.Errorf("GetSetting(currency) = %+v, want the default", v)
+		}
+		if GetSendByDefault() || !GetClipboard() || GetTimeout() != 30*time.Second {
+			t.Error("unexpected defaults for send, clipboard or timeout")
+		}
+	})
+
+	t.Run("set and unset", func(t *testing.T) {
+		if err := SetUserSetting("currency", "eur"); err != nil {
+			t.Fatalf("SetUserSetting() error = %v", err)
+		}
+		if err := SetUserSetting("send", "true"); err != nil {
+			t.Fatalf("SetUserSetting() error = %v", err)
+		}
+		if err := SetUserSetting("credential.store", StoreFile); err != nil {
+			t.Fatalf("SetUserSetting() error = %v", err)
+		}
+
+		v, _ := GetSetting("currency")
+		if v.Value != "eur" || v.Origin != path {
+			t.Errorf("GetSetting(currency) = %+v, want eur from %s", v, path)
+		}
+		if !GetSendByDefault() {
+			t.Error("GetSendByDefault() = false after setting it")
+		}
+		if v, _ := GetSetting("credential.store"); v.Value != StoreFile {
+			t.Errorf("GetSetting(credential.store) = %q, want %q", v.Value, StoreFile)
+		}
+
+		if err := UnsetUserSetting("currency"); err != nil {
+			t.Fatalf("UnsetUserSetting() error = %v", err)
+		}
+		if err := UnsetUserSetting("credential.store"); err != nil {
+			t.Fatalf("UnsetUserSetting() error = %v", err)
+		}
+		if got := GetDefaultCurrency(); got != DefaultCurrency {
+			t.Errorf("GetDefaultCurrency() = %q after unset, want %q", got, DefaultCurrency)
+		}
+
+		data, _ := os.ReadFile(path)
+		if string(data) != "send = true\n" {
+			t.Errorf("config.toml = %q, want only the send setting left", data)
+		}
+	})
+
+	t.Run("invalid values are refused", func(t *testing.T) {
+		cases := map[string]string{
+			"currency": "xyz",
+			"send":     "sometimes",
+			"timeout":  "-1s",
+			"output":   "csv",
+			"api_url":  "example.com",
+		}
+		for key, value := range cases {
+			if err := SetUserSetting(key, value); err == nil {
+				t.Errorf("SetUserSetting(%s, %s) expected error", key, value)
+			}
+		}
+		if err := SetUserSetting("colour", "red"); err == nil {
+			t.Error("SetUserSetting() expected error for an unknown key")
+		}
+	})
+
+	t.Run("precedence", func(t *testing.T) {
+		if err := SetUserSetting("currency", "gbp"); err != nil {
+			t.Fatalf("SetUserSetting() error = %v", err)
+		}
+		defer UnsetUserSetting("currency")
+
+		// The profile beats the user file
+		if err := SaveProfile(Profile{Name: "work", Currency: "eur"}); err != nil {
+			t.Fatalf("SaveProfile() error = %v", err)
+		}
+		SetProfile("work")
+		defer SetProfile("")
+
+		v, _ := GetSetting("currency")
+		if v.Value != "eur" || v.Origin != "profile work" {
+			t.Errorf("GetSetting(currency) = %+v, want eur from the profile", v)
+		}
+
+		// The environment beats the profile
+		t.Setenv("LANE_CURRENCY", "jpy")
+
+		v, _ = GetSetting("currency")
+		if v.Value != "jpy" || v.Origin != "env LANE_CURRENCY" {
+			t.Errorf("GetSetting(currency) = %+v, want jpy from the env", v)
+		}
+	})
+
+	t.Run("broken file", func(t *testing.T) {
+		if err := os.WriteFile(path, []byte("currency = \"xyz\"\n"), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		invalidateSettings() // As if edited before this run
+		defer func() {
+			os.Remove(path)
+			invalidateSettings()
+		}()
+
+		if err := CheckSettings(); err == nil {
+			t.Error("CheckSettings() expected error for an invalid currency")
+		}
+		if got := GetDefaultCurrency(); got != DefaultCurrency {
+			t.Errorf("GetDefaultCurrency() = %q, want the default while the file is broken", got)
+		}
+	})
+
+	t.Run("invalid env values", func(t *testing.T) {
+		cases := map[string]string{
+			"LANE_TIMEOUT": "abc",
+			"LANE_OUTPUT":  "xml",
+		}
+		for env, value := range cases {
+			t.Run(env, func(t *testing.T) {
+				t.Setenv(env, value)
+				err := CheckSettings()
+				if err == nil || !strings.Contains(err.Error(), "env "+env) {
+					t.Errorf("CheckSettings() with %s=%s error = %v, want it to name env %s", env, value, err, env)
+				}
+			})
+		}
+	})
+
+	t.Run("files are read once", func(t *testing.T) {
+		if err := SetUserSetting("theme", "dark"); err != nil {
+			t.Fatalf("SetUserSetting() error = %v", err)
+		}
+		defer UnsetUserSetting("theme")
+
+		if got := GetTheme(); got != "dark" {
+			t.Fatalf("GetTheme() = %q, want dark", got)
+		}
+		if err := os.WriteFile(path, []byte("theme = \"light\"\n"), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		if got := GetTheme(); got != "dark" {
+			t.Errorf("GetTheme() = %q, want the cached dark", got)
+		}
+
+		if err := SetUserSetting("theme", "none"); err != nil {
+			t.Fatalf("SetUserSetting() error = %v", err)
+		}
+		if got := GetTheme(); got != "none" {
+			t.Errorf("GetTheme() = %q after SetUserSetting, want none", got)
+		}
+	})
+
+	t.Run("XDG_CONFIG_HOME", func(t *testing.T) {
+		xdg := filepath.Join(home, "xdg")
+		t.Setenv(EnvXDGConfigHome, xdg)
+
+		if err := SetUserSetting("output", "json"); err != nil {
+			t.Fatalf("SetUserSetting() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(xdg, "lane", userConfigFile)); err != nil {
+			t.Errorf("config.toml not written under XDG_CONFIG_HOME: %v", err)
+		}
+		if got := GetOutputFormat(); got != "json" {
+			t.Errorf("GetOutputFormat() = %q, want json", got)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package currency
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Currency describes an ISO 4217 currency
+type Currency struct {
+	Code       string // ISO 4217 code, e.g. "USD"
+	Name       string // Display name, e.g. "US Dollar"
+	Symbol     string // Display symbol, empty when the code is used instead
+	MinorUnits int    // Decimal places in the minor unit (2 for cents)
+}
+
+// Lookup returns the currency for an ISO 4217 code (case-insensitive)
+func Lookup(code string) (Currency, bool) {
+	c, ok := currencies[strings.ToUpper(strings.TrimSpace(code))]
+	return c, ok
+}
+
+// All returns every known currency, sorted by code
+func All() []Currency {
+	all := make([]Currency, 0, len(currencies))
+	for _, c := range currencies {
+		all = append(all, c)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Code < all[j].Code })
+	return all
+}
+
+// Format renders an amount given in minor units, e.g. "€2,500.00",
+// "¥2500" or "KWD 1.250"
+func (c Currency) Format(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	prefix := c.Code + " "
+	if c.Symbol != "" {
+		prefix = c.Symbol
+	}
+
+	// Zero-decimal amounts are rendered as plain integers
+	if c.MinorUnits == 0 {
+		return fmt.Sprintf("%s%s%d", sign, prefix, amount)
+	}
+
+	scale := int64(1)
+	for i := 0; i < c.MinorUnits; i++ {
+		scale *= 10
+	}
+
+	whole := groupThousands(fmt.Sprintf("%d", amount/scale))
+	frac := fmt.Sprintf("%0*d", c.MinorUnits, amount%scale)
+	return sign + prefix + whole + "." + frac
+}
+
+// groupThousands inserts commas between groups of three digits
+func groupThousands(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+
+	var b strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		b.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package currency
+
+import "testing"
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		code       string
+		wantOK     bool
+		minorUnits int
+	}{
+		{"usd", true, 2},
+		{"EUR", true, 2},
+		{" jpy ", true, 0},
+		{"kwd", true, 3},
+		{"clf", false, 0}, // Fund code
+		{"xyz", false, 0},
+		{"", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, ok := Lookup(tt.code)
+			if ok != tt.wantOK {
+				t.Fatalf("Lookup(%q) ok = %v, want %v", tt.code, ok, tt.wantOK)
+			}
+			if ok && got.MinorUnits != tt.minorUnits {
+				t.Errorf("Lookup(%q).MinorUnits = %d, want %d", tt.code, got.MinorUnits, tt.minorUnits)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		code     string
+		amount   int64
+		expected string
+	}{
+		{"usd", 10000, "$100.00"},
+		{"usd", 5, "$0.05"},
+		{"usd", 123456789, "$1,234,567.89"},
+		{"eur", 250000, "€2,500.00"},
+		{"gbp", -4995, "-£49.95"},
+		{"jpy", 2500, "¥2500"},
+		{"aud", 2000, "A$20.00"},
+		{"inr", 150000, "₹1,500.00"},
+		{"zar", 9900, "R99.00"},
+		{"kwd", 1250, "KWD 1.250"},
+		{"chf", 100000, "CHF 1,000.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			cur, ok := Lookup(tt.code)
+			if !ok {
+				t.Fatalf("Lookup(%q) failed", tt.code)
+			}
+			if got := cur.Format(tt.amount); got != tt.expected {
+				t.Errorf("Format(%d) = %q, want %q", tt.amount, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAllSorted(t *testing.T) {
+	all := All()
+	if len(all) < 150 {
+		t.Fatalf("All() returned %d currencies, want the full ISO 4217 list", len(all))
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i-1].Code >= all[i].Code {
+			t.Fatalf("All() not sorted: %s before %s", all[i-1].Code, all[i].Code)
+		}
+	}
+}
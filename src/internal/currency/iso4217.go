@@ -0,0 +1,165 @@
+package currency
+
+// currencies holds every active ISO 4217 currency. Fund codes (CLF, USN,
+// ...) and precious metals codes (XAU, XDR, ...) are left out because they
+// cannot be invoiced.
+var currencies = map[string]Currency{
+	"AED": {Code: "AED", Name: "UAE Dirham", Symbol: "", MinorUnits: 2},
+	"AFN": {Code: "AFN", Name: "Afghani", Symbol: "", MinorUnits: 2},
+	"ALL": {Code: "ALL", Name: "Lek", Symbol: "", MinorUnits: 2},
+	"AMD": {Code: "AMD", Name: "Armenian Dram", Symbol: "", MinorUnits: 2},
+	"ANG": {Code: "ANG", Name: "Netherlands Antillean Guilder", Symbol: "", MinorUnits: 2},
+	"AOA": {Code: "AOA", Name: "Kwanza", Symbol: "", MinorUnits: 2},
+	"ARS": {Code: "ARS", Name: "Argentine Peso", Symbol: "", MinorUnits: 2},
+	"AUD": {Code: "AUD", Name: "Australian Dollar", Symbol: "A$", MinorUnits: 2},
+	"AWG": {Code: "AWG", Name: "Aruban Florin", Symbol: "", MinorUnits: 2},
+	"AZN": {Code: "AZN", Name: "Azerbaijan Manat", Symbol: "", MinorUnits: 2},
+	"BAM": {Code: "BAM", Name: "Convertible Mark", Symbol: "", MinorUnits: 2},
+	"BBD": {Code: "BBD", Name: "Barbados Dollar", Symbol: "", MinorUnits: 2},
+	"BDT": {Code: "BDT", Name: "Taka", Symbol: "", MinorUnits: 2},
+	"BGN": {Code: "BGN", Name: "Bulgarian Lev", Symbol: "", MinorUnits: 2},
+	"BHD": {Code: "BHD", Name: "Bahraini Dinar", Symbol: "", MinorUnits: 3},
+	"BIF": {Code: "BIF", Name: "Burundi Franc", Symbol: "", MinorUnits: 0},
+	"BMD": {Code: "BMD", Name: "Bermudian Dollar", Symbol: "", MinorUnits: 2},
+	"BND": {Code: "BND", Name: "Brunei Dollar", Symbol: "", MinorUnits: 2},
+	"BOB": {Code: "BOB", Name: "Boliviano", Symbol: "", MinorUnits: 2},
+	"BRL": {Code: "BRL", Name: "Brazilian Real", Symbol: "R$", MinorUnits: 2},
+	"BSD": {Code: "BSD", Name: "Bahamian Dollar", Symbol: "", MinorUnits: 2},
+	"BTN": {Code: "BTN", Name: "Ngultrum", Symbol: "", MinorUnits: 2},
+	"BWP": {Code: "BWP", Name: "Pula", Symbol: "", MinorUnits: 2},
+	"BYN": {Code: "BYN", Name: "Belarusian Ruble", Symbol: "", MinorUnits: 2},
+	"BZD": {Code: "BZD", Name: "Belize Dollar", Symbol: "", MinorUnits: 2},
+	"CAD": {Code: "CAD", Name: "Canadian Dollar", Symbol: "CA$", MinorUnits: 2},
+	"CDF": {Code: "CDF", Name: "Congolese Franc", Symbol: "", MinorUnits: 2},
+	"CHF": {Code: "CHF", Name: "Swiss Franc", Symbol: "", MinorUnits: 2},
+	"CLP": {Code: "CLP", Name: "Chilean Peso", Symbol: "", MinorUnits: 0},
+	"CNY": {Code: "CNY", Name: "Yuan Renminbi", Symbol: "CN¥", MinorUnits: 2},
+	"COP": {Code: "COP", Name: "Colombian Peso", Symbol: "", MinorUnits: 2},
+	"CRC": {Code: "CRC", Name: "Costa Rican Colon", Symbol: "₡", MinorUnits: 2},
+	"CUC": {Code: "CUC", Name: "Peso Convertible", Symbol: "", MinorUnits: 2},
+	"CUP": {Code: "CUP", Name: "Cuban Peso", Symbol: "", MinorUnits: 2},
+	"CVE": {Code: "CVE", Name: "Cabo Verde Escudo", Symbol: "", MinorUnits: 2},
+	"CZK": {Code: "CZK", Name: "Czech Koruna", Symbol: "", MinorUnits: 2},
+	"DJF": {Code: "DJF", Name: "Djibouti Franc", Symbol: "", MinorUnits: 0},
+	"DKK": {Code: "DKK", Name: "Danish Krone", Symbol: "", MinorUnits: 2},
+	"DOP": {Code: "DOP", Name: "Dominican Peso", Symbol: "", MinorUnits: 2},
+	"DZD": {Code: "DZD", Name: "Algerian Dinar", Symbol: "", MinorUnits: 2},
+	"EGP": {Code: "EGP", Name: "Egyptian Pound", Symbol: "", MinorUnits: 2},
+	"ERN": {Code: "ERN", Name: "Nakfa", Symbol: "", MinorUnits: 2},
+	"ETB": {Code: "ETB", Name: "Ethiopian Birr", Symbol: "", MinorUnits: 2},
+	"EUR": {Code: "EUR", Name: "Euro", Symbol: "€", MinorUnits: 2},
+	"FJD": {Code: "FJD", Name: "Fiji Dollar", Symbol: "", MinorUnits: 2},
+	"FKP": {Code: "FKP", Name: "Falkland Islands Pound", Symbol: "", MinorUnits: 2},
+	"GBP": {Code: "GBP", Name: "Pound Sterling", Symbol: "£", MinorUnits: 2},
+	"GEL": {Code: "GEL", Name: "Lari", Symbol: "₾", MinorUnits: 2},
+	"GHS": {Code: "GHS", Name: "Ghana Cedi", Symbol: "", MinorUnits: 2},
+	"GIP": {Code: "GIP", Name: "Gibraltar Pound", Symbol: "", MinorUnits: 2},
+	"GMD": {Code: "GMD", Name: "Dalasi", Symbol: "", MinorUnits: 2},
+	"GNF": {Code: "GNF", Name: "Guinean Franc", Symbol: "", MinorUnits: 0},
+	"GTQ": {Code: "GTQ", Name: "Quetzal", Symbol: "", MinorUnits: 2},
+	"GYD": {Code: "GYD", Name: "Guyana Dollar", Symbol: "", MinorUnits: 2},
+	"HKD": {Code: "HKD", Name: "Hong Kong Dollar", Symbol: "HK$", MinorUnits: 2},
+	"HNL": {Code: "HNL", Name: "Lempira", Symbol: "", MinorUnits: 2},
+	"HTG": {Code: "HTG", Name: "Gourde", Symbol: "", MinorUnits: 2},
+	"HUF": {Code: "HUF", Name: "Forint", Symbol: "", MinorUnits: 2},
+	"IDR": {Code: "IDR", Name: "Rupiah", Symbol: "Rp", MinorUnits: 2},
+	"ILS": {Code: "ILS", Name: "New Israeli Sheqel", Symbol: "₪", MinorUnits: 2},
+	"INR": {Code: "INR", Name: "Indian Rupee", Symbol: "₹", MinorUnits: 2},
+	"IQD": {Code: "IQD", Name: "Iraqi Dinar", Symbol: "", MinorUnits: 3},
+	"IRR": {Code: "IRR", Name: "Iranian Rial", Symbol: "", MinorUnits: 2},
+	"ISK": {Code: "ISK", Name: "Iceland Krona", Symbol: "", MinorUnits: 0},
+	"JMD": {Code: "JMD", Name: "Jamaican Dollar", Symbol: "", MinorUnits: 2},
+	"JOD": {Code: "JOD", Name: "Jordanian Dinar", Symbol: "", MinorUnits: 3},
+	"JPY": {Code: "JPY", Name: "Yen", Symbol: "¥", MinorUnits: 0},
+	"KES": {Code: "KES", Name: "Kenyan Shilling", Symbol: "", MinorUnits: 2},
+	"KGS": {Code: "KGS", Name: "Som", Symbol: "", MinorUnits: 2},
+	"KHR": {Code: "KHR", Name: "Riel", Symbol: "", MinorUnits: 2},
+	"KMF": {Code: "KMF", Name: "Comorian Franc", Symbol: "", MinorUnits: 0},
+	"KPW": {Code: "KPW", Name: "North Korean Won", Symbol: "", MinorUnits: 2},
+	"KRW": {Code: "KRW", Name: "Won", Symbol: "₩", MinorUnits: 0},
+	"KWD": {Code: "KWD", Name: "Kuwaiti Dinar", Symbol: "", MinorUnits: 3},
+	"KYD": {Code: "KYD", Name: "Cayman Islands Dollar", Symbol: "", MinorUnits: 2},
+	"KZT": {Code: "KZT", Name: "Tenge", Symbol: "₸", MinorUnits: 2},
+	"LAK": {Code: "LAK", Name: "Lao Kip", Symbol: "", MinorUnits: 2},
+	"LBP": {Code: "LBP", Name: "Lebanese Pound", Symbol: "", MinorUnits: 2},
+	"LKR": {Code: "LKR", Name: "Sri Lanka Rupee", Symbol: "", MinorUnits: 2},
+	"LRD": {Code: "LRD", Name: "Liberian Dollar", Symbol: "", MinorUnits: 2},
+	"LSL": {Code: "LSL", Name: "Loti", Symbol: "", MinorUnits: 2},
+	"LYD": {Code: "LYD", Name: "Libyan Dinar", Symbol: "", MinorUnits: 3},
+	"MAD": {Code: "MAD", Name: "Moroccan Dirham", Symbol: "", MinorUnits: 2},
+	"MDL": {Code: "MDL", Name: "Moldovan Leu", Symbol: "", MinorUnits: 2},
+	"MGA": {Code: "MGA", Name: "Malagasy Ariary", Symbol: "", MinorUnits: 2},
+	"MKD": {Code: "MKD", Name: "Denar", Symbol: "", MinorUnits: 2},
+	"MMK": {Code: "MMK", Name: "Kyat", Symbol: "", MinorUnits: 2},
+	"MNT": {Code: "MNT", Name: "Tugrik", Symbol: "₮", MinorUnits: 2},
+	"MOP": {Code: "MOP", Name: "Pataca", Symbol: "", MinorUnits: 2},
+	"MRU": {Code: "MRU", Name: "Ouguiya", Symbol: "", MinorUnits: 2},
+	"MUR": {Code: "MUR", Name: "Mauritius Rupee", Symbol: "", MinorUnits: 2},
+	"MVR": {Code: "MVR", Name: "Rufiyaa", Symbol: "", MinorUnits: 2},
+	"MWK": {Code: "MWK", Name: "Malawi Kwacha", Symbol: "", MinorUnits: 2},
+	"MXN": {Code: "MXN", Name: "Mexican Peso", Symbol: "MX$", MinorUnits: 2},
+	"MYR": {Code: "MYR", Name: "Malaysian Ringgit", Symbol: "RM", MinorUnits: 2},
+	"MZN": {Code: "MZN", Name: "Mozambique Metical", Symbol: "", MinorUnits: 2},
+	"NAD": {Code: "NAD", Name: "Namibia Dollar", Symbol: "", MinorUnits: 2},
+	"NGN": {Code: "NGN", Name: "Naira", Symbol: "₦", MinorUnits: 2},
+	"NIO": {Code: "NIO", Name: "Cordoba Oro", Symbol: "", MinorUnits: 2},
+	"NOK": {Code: "NOK", Name: "Norwegian Krone", Symbol: "", MinorUnits: 2},
+	"NPR": {Code: "NPR", Name: "Nepalese Rupee", Symbol: "", MinorUnits: 2},
+	"NZD": {Code: "NZD", Name: "New Zealand Dollar", Symbol: "NZ$", MinorUnits: 2},
+	"OMR": {Code: "OMR", Name: "Rial Omani", Symbol: "", MinorUnits: 3},
+	"PAB": {Code: "PAB", Name: "Balboa", Symbol: "", MinorUnits: 2},
+	"PEN": {Code: "PEN", Name: "Sol", Symbol: "", MinorUnits: 2},
+	"PGK": {Code: "PGK", Name: "Kina", Symbol: "", MinorUnits: 2},
+	"PHP": {Code: "PHP", Name: "Philippine Peso", Symbol: "₱", MinorUnits: 2},
+	"PKR": {Code: "PKR", Name: "Pakistan Rupee", Symbol: "", MinorUnits: 2},
+	"PLN": {Code: "PLN", Name: "Zloty", Symbol: "", MinorUnits: 2},
+	"PYG": {Code: "PYG", Name: "Guarani", Symbol: "₲", MinorUnits: 0},
+	"QAR": {Code: "QAR", Name: "Qatari Rial", Symbol: "", MinorUnits: 2},
+	"RON": {Code: "RON", Name: "Romanian Leu", Symbol: "", MinorUnits: 2},
+	"RSD": {Code: "RSD", Name: "Serbian Dinar", Symbol: "", MinorUnits: 2},
+	"RUB": {Code: "RUB", Name: "Russian Ruble", Symbol: "₽", MinorUnits: 2},
+	"RWF": {Code: "RWF", Name: "Rwanda Franc", Symbol: "", MinorUnits: 0},
+	"SAR": {Code: "SAR", Name: "Saudi Riyal", Symbol: "", MinorUnits: 2},
+	"SBD": {Code: "SBD", Name: "Solomon Islands Dollar", Symbol: "", MinorUnits: 2},
+	"SCR": {Code: "SCR", Name: "Seychelles Rupee", Symbol: "", MinorUnits: 2},
+	"SDG": {Code: "SDG", Name: "Sudanese Pound", Symbol: "", MinorUnits: 2},
+	"SEK": {Code: "SEK", Name: "Swedish Krona", Symbol: "", MinorUnits: 2},
+	"SGD": {Code: "SGD", Name: "Singapore Dollar", Symbol: "S$", MinorUnits: 2},
+	"SHP": {Code: "SHP", Name: "Saint Helena Pound", Symbol: "", MinorUnits: 2},
+	"SLE": {Code: "SLE", Name: "Leone", Symbol: "", MinorUnits: 2},
+	"SOS": {Code: "SOS", Name: "Somali Shilling", Symbol: "", MinorUnits: 2},
+	"SRD": {Code: "SRD", Name: "Surinam Dollar", Symbol: "", MinorUnits: 2},
+	"SSP": {Code: "SSP", Name: "South Sudanese Pound", Symbol: "", MinorUnits: 2},
+	"STN": {Code: "STN", Name: "Dobra", Symbol: "", MinorUnits: 2},
+	"SVC": {Code: "SVC", Name: "El Salvador Colon", Symbol: "", MinorUnits: 2},
+	"SYP": {Code: "SYP", Name: "Syrian Pound", Symbol: "", MinorUnits: 2},
+	"SZL": {Code: "SZL", Name: "Lilangeni", Symbol: "", MinorUnits: 2},
+	"THB": {Code: "THB", Name: "Baht", Symbol: "฿", MinorUnits: 2},
+	"TJS": {Code: "TJS", Name: "Somoni", Symbol: "", MinorUnits: 2},
+	"TMT": {Code: "TMT", Name: "Turkmenistan New Manat", Symbol: "", MinorUnits: 2},
+	"TND": {Code: "TND", Name: "Tunisian Dinar", Symbol: "", MinorUnits: 3},
+	"TOP": {Code: "TOP", Name: "Pa'anga", Symbol: "", MinorUnits: 2},
+	"TRY": {Code: "TRY", Name: "Turkish Lira", Symbol: "₺", MinorUnits: 2},
+	"TTD": {Code: "TTD", Name: "Trinidad and Tobago Dollar", Symbol: "", MinorUnits: 2},
+	"TWD": {Code: "TWD", Name: "New Taiwan Dollar", Symbol: "NT$", MinorUnits: 2},
+	"TZS": {Code: "TZS", Name: "Tanzanian Shilling", Symbol: "", MinorUnits: 2},
+	"UAH": {Code: "UAH", Name: "Hryvnia", Symbol: "₴", MinorUnits: 2},
+	"UGX": {Code: "UGX", Name: "Uganda Shilling", Symbol: "", MinorUnits: 0},
+	"USD": {Code: "USD", Name: "US Dollar", Symbol: "$", MinorUnits: 2},
+	"UYU": {Code: "UYU", Name: "Peso Uruguayo", Symbol: "", MinorUnits: 2},
+	"UZS": {Code: "UZS", Name: "Uzbekistan Sum", Symbol: "", MinorUnits: 2},
+	"VED": {Code: "VED", Name: "Bolivar Soberano", Symbol: "", MinorUnits: 2},
+	"VES": {Code: "VES", Name: "Bolivar Soberano", Symbol: "", MinorUnits: 2},
+	"VND": {Code: "VND", Name: "Dong", Symbol: "₫", MinorUnits: 0},
+	"VUV": {Code: "VUV", Name: "Vatu", Symbol: "", MinorUnits: 0},
+	"WST": {Code: "WST", Name: "Tala", Symbol: "", MinorUnits: 2},
+	"XAF": {Code: "XAF", Name: "CFA Franc BEAC", Symbol: "", MinorUnits: 0},
+	"XCD": {Code: "XCD", Name: "East Caribbean Dollar", Symbol: "EC$", MinorUnits: 2},
+	"XCG": {Code: "XCG", Name: "Caribbean Guilder", Symbol: "", MinorUnits: 2},
+	"XOF": {Code: "XOF", Name: "CFA Franc BCEAO", Symbol: "", MinorUnits: 0},
+	"XPF": {Code: "XPF", Name: "CFP Franc", Symbol: "", MinorUnits: 0},
+	"YER": {Code: "YER", Name: "Yemeni Rial", Symbol: "", MinorUnits: 2},
+	"ZAR": {Code: "ZAR", Name: "Rand", Symbol: "R", MinorUnits: 2},
+	"ZMW": {Code: "ZMW", Name: "Zambian Kwacha", Symbol: "", MinorUnits: 2},
+	"ZWG": {Code: "ZWG", Name: "Zimbabwe Gold", Symbol: "", MinorUnits: 2},
+}
@@ -0,0 +1,24 @@
+// Package testutil holds helpers shared by the tests of several packages.
+// It is only imported from _test.go files.
+package testutil
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// RecordSleeps replaces the sleep function behind hook for the test with
+// one that returns at once, and returns the waits it was asked for
+func RecordSleeps(t testing.TB, hook *func(context.Context, time.Duration) error) *[]time.Duration {
+	t.Helper()
+
+	var waits []time.Duration
+	original := *hook
+	*hook = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	t.Cleanup(func() { *hook = original })
+	return &waits
+}
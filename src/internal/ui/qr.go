@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/skip2/go-qrcode"
+)
+
+// QR code colors are fixed so the code scans on light and dark terminals
+var qrStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#000000")).
+	Background(lipgloss.Color("#FFFFFF"))
+
+// QRCode renders content as a QR code using Unicode half-blocks, packing
+// two rows of modules into each line of text
+func QRCode(content string) (string, error) {
+	qr, err := qrcode.New(content, qrcode.Low)
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for _, line := range qrLines(qr.Bitmap()) {
+		lines = append(lines, qrStyle.Render(line))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// qrLines draws a module bitmap (true = dark) two rows per line
+func qrLines(bitmap [][]bool) []string {
+	lines := make([]string, 0, (len(bitmap)+1)/2)
+	for y := 0; y < len(bitmap); y += 2 {
+		var line strings.Builder
+		for x := range bitmap[y] {
+			top := bitmap[y][x]
+			bottom := y+1 < len(bitmap) && bitmap[y+1][x]
+			switch {
+			case top && bottom:
+				line.WriteString("█")
+			case top:
+				line.WriteString("▀")
+			case bottom:
+				line.WriteString("▄")
+			default:
+				line.WriteString(" ")
+			}
+		}
+		lines = append(lines, line.String())
+	}
+	return lines
+}
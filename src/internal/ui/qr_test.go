@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQRLines(t *testing.T) {
+	bitmap := [][]bool{
+		{true, true, false, false},
+		{true, false, true, false},
+		{false, true, false, true},
+	}
+
+	got := qrLines(bitmap)
+	want := []string{"█▀▄ ", " ▀ ▀"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("qrLines() = %q, want %q", got, want)
+	}
+}
+
+func TestQRCode(t *testing.T) {
+	got, err := QRCode("https://lane.example.com/auth/cli?code=ABCD-1234")
+	if err != nil {
+		t.Fatalf("QRCode() error = %v", err)
+	}
+
+	lines := strings.Split(got, "\n")
+	if len(lines) < 10 {
+		t.Errorf("QRCode() rendered %d lines, want a full code", len(lines))
+	}
+	if !strings.ContainsAny(got, "█▀▄") {
+		t.Error("QRCode() contains no blocks")
+	}
+}
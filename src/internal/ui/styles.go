@@ -1,9 +1,9 @@
 package ui
 
 import (
-	"fmt"
-
 	"github.com/charmbracelet/lipgloss"
+	"github.com/forrestcai35/lane/internal/currency"
+	"github.com/muesli/termenv"
 )
 
 var (
@@ -65,10 +65,22 @@ var (
 			Foreground(Purple)
 )
 
-// FormatAmount formats a dollar amount with styling
-func FormatAmount(cents int64) string {
-	dollars := float64(cents) / 100
-	return Highlight.Render("$" + formatFloat(dollars))
+// SetTheme applies a color theme: "dark" or "light" skip background
+// detection, "none" turns colors off and "auto" leaves both to the terminal
+func SetTheme(theme string) {
+	switch theme {
+	case "dark":
+		lipgloss.SetHasDarkBackground(true)
+	case "light":
+		lipgloss.SetHasDarkBackground(false)
+	case "none":
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// FormatAmount formats an amount in the currency's minor units with styling
+func FormatAmount(amount int64, cur currency.Currency) string {
+	return Highlight.Render(cur.Format(amount))
 }
 
 // FormatLink formats a URL with styling
@@ -95,10 +107,3 @@ func FormatError(msg string) string {
 func FormatStep(msg string) string {
 	return Progress.Render("→ ") + msg
 }
-
-func formatFloat(f float64) string {
-	if f == float64(int64(f)) {
-		return fmt.Sprintf("%.0f", f)
-	}
-	return fmt.Sprintf("%.2f", f)
-}
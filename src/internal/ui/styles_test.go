@@ -3,26 +3,33 @@ package ui
 import (
 	"strings"
 	"testing"
+
+	"github.com/forrestcai35/lane/internal/currency"
 )
 
 func TestFormatAmount(t *testing.T) {
 	tests := []struct {
 		cents    int64
+		currency string
 		contains string
 	}{
-		{10000, "$100"},
-		{9999, "$99.99"},
-		{50, "$0.50"},
-		{100, "$1"},
-		{12345, "$123.45"},
+		{10000, "usd", "$100"},
+		{9999, "usd", "$99.99"},
+		{50, "usd", "$0.50"},
+		{100, "usd", "$1"},
+		{12345, "usd", "$123.45"},
+		{250000, "eur", "€2,500.00"},
+		{2500, "jpy", "¥2500"},
+		{1250, "kwd", "KWD 1.250"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.contains, func(t *testing.T) {
-			got := FormatAmount(tt.cents)
+			cur, _ := currency.Lookup(tt.currency)
+			got := FormatAmount(tt.cents, cur)
 			// The output includes ANSI codes, so just check the value is present
 			if !strings.Contains(got, tt.contains) {
-				t.Errorf("FormatAmount(%d) = %q, want to contain %q", tt.cents, got, tt.contains)
+				t.Errorf("FormatAmount(%d, %s) = %q, want to contain %q", tt.cents, tt.currency, got, tt.contains)
 			}
 		})
 	}
@@ -75,25 +82,3 @@ func TestFormatLink(t *testing.T) {
 		t.Errorf("FormatLink() missing URL")
 	}
 }
-
-func TestFormatFloat(t *testing.T) {
-	tests := []struct {
-		input    float64
-		expected string
-	}{
-		{100.0, "100"},
-		{99.99, "99.99"},
-		{0.5, "0.50"},
-		{1.1, "1.10"},
-		{1000.0, "1000"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.expected, func(t *testing.T) {
-			got := formatFloat(tt.input)
-			if got != tt.expected {
-				t.Errorf("formatFloat(%v) = %q, want %q", tt.input, got, tt.expected)
-			}
-		})
-	}
-}
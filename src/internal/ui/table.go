@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+var (
+	// Table header cells
+	TableHeader = lipgloss.NewStyle().
+			Foreground(LightPurple).
+			Bold(true).
+			Padding(0, 1)
+
+	// Table body cells
+	TableCell = lipgloss.NewStyle().
+			Foreground(White).
+			Padding(0, 1)
+)
+
+// Table renders rows under the given headers. Columns listed in
+// rightAlign (amounts, quantities) are right-aligned.
+func Table(headers []string, rows [][]string, rightAlign ...int) string {
+	right := make(map[int]bool, len(rightAlign))
+	for _, col := range rightAlign {
+		right[col] = true
+	}
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(DarkPurple)).
+		BorderTop(false).
+		BorderBottom(false).
+		BorderLeft(false).
+		BorderRight(false).
+		BorderColumn(false).
+		Headers(headers...).
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			style := TableCell
+			if row == 0 {
+				style = TableHeader
+			}
+			if right[col] {
+				style = style.Copy().Align(lipgloss.Right)
+			}
+			return style
+		})
+
+	return t.Render()
+}
@@ -0,0 +1,19 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+	got := Table(
+		[]string{"Item", "Amount"},
+		[][]string{{"Design", "$450.00"}, {"Hosting", "$20.00"}},
+		1,
+	)
+	for _, want := range []string{"Item", "Amount", "Design", "$450.00", "Hosting", "$20.00"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Table() missing %q", want)
+		}
+	}
+}